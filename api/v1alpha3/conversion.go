@@ -19,6 +19,7 @@ package v1alpha3
 import (
 	"github.com/spectrocloud/cluster-api-provider-maas/api/v1beta1"
 	apiconversion "k8s.io/apimachinery/pkg/conversion"
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 	"unsafe"
 )
@@ -26,13 +27,32 @@ import (
 func (in *MaasCluster) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*v1beta1.MaasCluster)
 
-	return Convert_v1alpha3_MaasCluster_To_v1beta1_MaasCluster(in, dst, nil)
+	if err := Convert_v1alpha3_MaasCluster_To_v1beta1_MaasCluster(in, dst, nil); err != nil {
+		return err
+	}
+
+	// Restore the v1beta1 only fields preserved on down-conversion
+	restored := &v1beta1.MaasCluster{}
+	if ok, err := utilconversion.UnmarshalData(in, restored); err != nil || !ok {
+		return err
+	}
+	dst.Spec.AutoDiscoverFailureDomains = restored.Spec.AutoDiscoverFailureDomains
+	dst.Spec.ProviderIDFormat = restored.Spec.ProviderIDFormat
+	dst.Status.Capacity = restored.Status.Capacity
+	dst.Status.Network.AttachedIPs = restored.Status.Network.AttachedIPs
+
+	return nil
 }
 
 func (in *MaasCluster) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*v1beta1.MaasCluster)
 
-	return Convert_v1beta1_MaasCluster_To_v1alpha3_MaasCluster(src, in, nil)
+	if err := Convert_v1beta1_MaasCluster_To_v1alpha3_MaasCluster(src, in, nil); err != nil {
+		return err
+	}
+
+	// Preserve the hub data in an annotation so no fields are lost on the round trip
+	return utilconversion.MarshalData(src, in)
 }
 
 func (in *MaasClusterList) ConvertTo(dstRaw conversion.Hub) error {
@@ -50,13 +70,32 @@ func (in *MaasClusterList) ConvertFrom(srcRaw conversion.Hub) error {
 func (in *MaasMachine) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*v1beta1.MaasMachine)
 
-	return Convert_v1alpha3_MaasMachine_To_v1beta1_MaasMachine(in, dst, nil)
+	if err := Convert_v1alpha3_MaasMachine_To_v1beta1_MaasMachine(in, dst, nil); err != nil {
+		return err
+	}
+
+	// Restore the v1beta1 only fields preserved on down-conversion
+	restored := &v1beta1.MaasMachine{}
+	if ok, err := utilconversion.UnmarshalData(in, restored); err != nil || !ok {
+		return err
+	}
+	dst.Spec.Tags = restored.Spec.Tags
+	dst.Spec.ReleasePolicy = restored.Spec.ReleasePolicy
+	dst.Spec.ManagePower = restored.Spec.ManagePower
+	dst.Spec.NodeLabelPrefixes = restored.Spec.NodeLabelPrefixes
+
+	return nil
 }
 
 func (in *MaasMachine) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*v1beta1.MaasMachine)
 
-	return Convert_v1beta1_MaasMachine_To_v1alpha3_MaasMachine(src, in, nil)
+	if err := Convert_v1beta1_MaasMachine_To_v1alpha3_MaasMachine(src, in, nil); err != nil {
+		return err
+	}
+
+	// Preserve the hub data in an annotation so no fields are lost on the round trip
+	return utilconversion.MarshalData(src, in)
 }
 
 func (in *MaasMachineList) ConvertTo(dstRaw conversion.Hub) error {
@@ -74,13 +113,33 @@ func (in *MaasMachineList) ConvertFrom(srcRaw conversion.Hub) error {
 func (in *MaasMachineTemplate) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*v1beta1.MaasMachineTemplate)
 
-	return Convert_v1alpha3_MaasMachineTemplate_To_v1beta1_MaasMachineTemplate(in, dst, nil)
+	if err := Convert_v1alpha3_MaasMachineTemplate_To_v1beta1_MaasMachineTemplate(in, dst, nil); err != nil {
+		return err
+	}
+
+	// Restore the v1beta1 only fields preserved on down-conversion
+	restored := &v1beta1.MaasMachineTemplate{}
+	if ok, err := utilconversion.UnmarshalData(in, restored); err != nil || !ok {
+		return err
+	}
+	dst.Spec.Template.Spec.Tags = restored.Spec.Template.Spec.Tags
+	dst.Spec.Template.Spec.ReleasePolicy = restored.Spec.Template.Spec.ReleasePolicy
+	dst.Spec.Template.Spec.ManagePower = restored.Spec.Template.Spec.ManagePower
+	dst.Spec.Template.Spec.NodeLabelPrefixes = restored.Spec.Template.Spec.NodeLabelPrefixes
+	dst.Spec.Defaults = restored.Spec.Defaults
+
+	return nil
 }
 
 func (in *MaasMachineTemplate) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*v1beta1.MaasMachineTemplate)
 
-	return Convert_v1beta1_MaasMachineTemplate_To_v1alpha3_MaasMachineTemplate(src, in, nil)
+	if err := Convert_v1beta1_MaasMachineTemplate_To_v1alpha3_MaasMachineTemplate(src, in, nil); err != nil {
+		return err
+	}
+
+	// Preserve the hub data in an annotation so no fields are lost on the round trip
+	return utilconversion.MarshalData(src, in)
 }
 
 func (in *MaasMachineTemplateList) ConvertTo(dstRaw conversion.Hub) error {
@@ -95,6 +154,22 @@ func (in *MaasMachineTemplateList) ConvertFrom(srcRaw conversion.Hub) error {
 	return Convert_v1beta1_MaasMachineTemplateList_To_v1alpha3_MaasMachineTemplateList(src, in, nil)
 }
 
+func Convert_v1beta1_MaasClusterSpec_To_v1alpha3_MaasClusterSpec(in *v1beta1.MaasClusterSpec, out *MaasClusterSpec, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_MaasClusterSpec_To_v1alpha3_MaasClusterSpec(in, out, s)
+}
+
+func Convert_v1beta1_MaasClusterStatus_To_v1alpha3_MaasClusterStatus(in *v1beta1.MaasClusterStatus, out *MaasClusterStatus, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_MaasClusterStatus_To_v1alpha3_MaasClusterStatus(in, out, s)
+}
+
+func Convert_v1beta1_MaasMachineTemplateSpec_To_v1alpha3_MaasMachineTemplateSpec(in *v1beta1.MaasMachineTemplateSpec, out *MaasMachineTemplateSpec, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_MaasMachineTemplateSpec_To_v1alpha3_MaasMachineTemplateSpec(in, out, s)
+}
+
+func Convert_v1beta1_Network_To_v1alpha3_Network(in *v1beta1.Network, out *Network, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_Network_To_v1alpha3_Network(in, out, s)
+}
+
 func Convert_v1beta1_MaasMachineSpec_To_v1alpha3_MaasMachineSpec(in *v1beta1.MaasMachineSpec, out *MaasMachineSpec, s apiconversion.Scope) error {
 	if err := autoConvert_v1beta1_MaasMachineSpec_To_v1alpha3_MaasMachineSpec(in, out, s); err != nil {
 		return err
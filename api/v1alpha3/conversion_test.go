@@ -50,3 +50,27 @@ func TestFuzzyConversion(t *testing.T) {
 		Spoke:  &MaasMachineTemplate{},
 	}))
 }
+
+func TestMaasMachineSpecConversion(t *testing.T) {
+	g := NewWithT(t)
+	minCPU := 4
+	minMemory := 8192
+
+	machine := &MaasMachine{
+		Spec: MaasMachineSpec{
+			MinCPU:    &minCPU,
+			MinMemory: &minMemory,
+			Image:     "u-2004-0-k-1243-0",
+		},
+	}
+
+	hub := &v1beta1.MaasMachine{}
+	g.Expect(machine.ConvertTo(hub)).To(Succeed())
+	g.Expect(hub.Spec.MinCPU).To(Equal(&minCPU))
+	g.Expect(hub.Spec.MinMemoryInMB).To(Equal(&minMemory))
+
+	restored := &MaasMachine{}
+	g.Expect(restored.ConvertFrom(hub)).To(Succeed())
+	g.Expect(restored.Spec.MinCPU).To(Equal(&minCPU))
+	g.Expect(restored.Spec.MinMemory).To(Equal(&minMemory))
+}
@@ -74,21 +74,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.MaasClusterSpec)(nil), (*MaasClusterSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_MaasClusterSpec_To_v1alpha3_MaasClusterSpec(a.(*v1beta1.MaasClusterSpec), b.(*MaasClusterSpec), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*MaasClusterStatus)(nil), (*v1beta1.MaasClusterStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_MaasClusterStatus_To_v1beta1_MaasClusterStatus(a.(*MaasClusterStatus), b.(*v1beta1.MaasClusterStatus), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.MaasClusterStatus)(nil), (*MaasClusterStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_MaasClusterStatus_To_v1alpha3_MaasClusterStatus(a.(*v1beta1.MaasClusterStatus), b.(*MaasClusterStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*MaasMachine)(nil), (*v1beta1.MaasMachine)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_MaasMachine_To_v1beta1_MaasMachine(a.(*MaasMachine), b.(*v1beta1.MaasMachine), scope)
 	}); err != nil {
@@ -154,11 +144,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.MaasMachineTemplateSpec)(nil), (*MaasMachineTemplateSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_MaasMachineTemplateSpec_To_v1alpha3_MaasMachineTemplateSpec(a.(*v1beta1.MaasMachineTemplateSpec), b.(*MaasMachineTemplateSpec), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*Machine)(nil), (*v1beta1.Machine)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_Machine_To_v1beta1_Machine(a.(*Machine), b.(*v1beta1.Machine), scope)
 	}); err != nil {
@@ -174,8 +159,13 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.Network)(nil), (*Network)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_Network_To_v1alpha3_Network(a.(*v1beta1.Network), b.(*Network), scope)
+	if err := s.AddConversionFunc((*v1beta1.MaasClusterSpec)(nil), (*MaasClusterSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_MaasClusterSpec_To_v1alpha3_MaasClusterSpec(a.(*v1beta1.MaasClusterSpec), b.(*MaasClusterSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1beta1.MaasClusterStatus)(nil), (*MaasClusterStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_MaasClusterStatus_To_v1alpha3_MaasClusterStatus(a.(*v1beta1.MaasClusterStatus), b.(*MaasClusterStatus), scope)
 	}); err != nil {
 		return err
 	}
@@ -189,6 +179,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1beta1.MaasMachineTemplateSpec)(nil), (*MaasMachineTemplateSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_MaasMachineTemplateSpec_To_v1alpha3_MaasMachineTemplateSpec(a.(*v1beta1.MaasMachineTemplateSpec), b.(*MaasMachineTemplateSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1beta1.Network)(nil), (*Network)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_Network_To_v1alpha3_Network(a.(*v1beta1.Network), b.(*Network), scope)
+	}); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -248,7 +248,17 @@ func Convert_v1beta1_MaasCluster_To_v1alpha3_MaasCluster(in *v1beta1.MaasCluster
 
 func autoConvert_v1alpha3_MaasClusterList_To_v1beta1_MaasClusterList(in *MaasClusterList, out *v1beta1.MaasClusterList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]v1beta1.MaasCluster)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]v1beta1.MaasCluster, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha3_MaasCluster_To_v1beta1_MaasCluster(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -259,7 +269,17 @@ func Convert_v1alpha3_MaasClusterList_To_v1beta1_MaasClusterList(in *MaasCluster
 
 func autoConvert_v1beta1_MaasClusterList_To_v1alpha3_MaasClusterList(in *v1beta1.MaasClusterList, out *MaasClusterList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]MaasCluster)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MaasCluster, len(*in))
+		for i := range *in {
+			if err := Convert_v1beta1_MaasCluster_To_v1alpha3_MaasCluster(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -288,14 +308,11 @@ func autoConvert_v1beta1_MaasClusterSpec_To_v1alpha3_MaasClusterSpec(in *v1beta1
 		return err
 	}
 	out.FailureDomains = *(*[]string)(unsafe.Pointer(&in.FailureDomains))
+	// WARNING: in.AutoDiscoverFailureDomains requires manual conversion: does not exist in peer-type
+	// WARNING: in.ProviderIDFormat requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta1_MaasClusterSpec_To_v1alpha3_MaasClusterSpec is an autogenerated conversion function.
-func Convert_v1beta1_MaasClusterSpec_To_v1alpha3_MaasClusterSpec(in *v1beta1.MaasClusterSpec, out *MaasClusterSpec, s conversion.Scope) error {
-	return autoConvert_v1beta1_MaasClusterSpec_To_v1alpha3_MaasClusterSpec(in, out, s)
-}
-
 func autoConvert_v1alpha3_MaasClusterStatus_To_v1beta1_MaasClusterStatus(in *MaasClusterStatus, out *v1beta1.MaasClusterStatus, s conversion.Scope) error {
 	out.Ready = in.Ready
 	if err := Convert_v1alpha3_Network_To_v1beta1_Network(&in.Network, &out.Network, s); err != nil {
@@ -318,14 +335,10 @@ func autoConvert_v1beta1_MaasClusterStatus_To_v1alpha3_MaasClusterStatus(in *v1b
 	}
 	out.FailureDomains = *(*apiv1alpha3.FailureDomains)(unsafe.Pointer(&in.FailureDomains))
 	out.Conditions = *(*apiv1alpha3.Conditions)(unsafe.Pointer(&in.Conditions))
+	// WARNING: in.Capacity requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta1_MaasClusterStatus_To_v1alpha3_MaasClusterStatus is an autogenerated conversion function.
-func Convert_v1beta1_MaasClusterStatus_To_v1alpha3_MaasClusterStatus(in *v1beta1.MaasClusterStatus, out *MaasClusterStatus, s conversion.Scope) error {
-	return autoConvert_v1beta1_MaasClusterStatus_To_v1alpha3_MaasClusterStatus(in, out, s)
-}
-
 func autoConvert_v1alpha3_MaasMachine_To_v1beta1_MaasMachine(in *MaasMachine, out *v1beta1.MaasMachine, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha3_MaasMachineSpec_To_v1beta1_MaasMachineSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -420,6 +433,9 @@ func autoConvert_v1beta1_MaasMachineSpec_To_v1alpha3_MaasMachineSpec(in *v1beta1
 	// WARNING: in.MinMemoryInMB requires manual conversion: does not exist in peer-type
 	// WARNING: in.Tags requires manual conversion: does not exist in peer-type
 	out.Image = in.Image
+	// WARNING: in.ReleasePolicy requires manual conversion: does not exist in peer-type
+	// WARNING: in.ManagePower requires manual conversion: does not exist in peer-type
+	// WARNING: in.NodeLabelPrefixes requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -567,14 +583,10 @@ func autoConvert_v1beta1_MaasMachineTemplateSpec_To_v1alpha3_MaasMachineTemplate
 	if err := Convert_v1beta1_MaasMachineTemplateResource_To_v1alpha3_MaasMachineTemplateResource(&in.Template, &out.Template, s); err != nil {
 		return err
 	}
+	// WARNING: in.Defaults requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta1_MaasMachineTemplateSpec_To_v1alpha3_MaasMachineTemplateSpec is an autogenerated conversion function.
-func Convert_v1beta1_MaasMachineTemplateSpec_To_v1alpha3_MaasMachineTemplateSpec(in *v1beta1.MaasMachineTemplateSpec, out *MaasMachineTemplateSpec, s conversion.Scope) error {
-	return autoConvert_v1beta1_MaasMachineTemplateSpec_To_v1alpha3_MaasMachineTemplateSpec(in, out, s)
-}
-
 func autoConvert_v1alpha3_Machine_To_v1beta1_Machine(in *Machine, out *v1beta1.Machine, s conversion.Scope) error {
 	out.ID = in.ID
 	out.Hostname = in.Hostname
@@ -617,10 +629,6 @@ func Convert_v1alpha3_Network_To_v1beta1_Network(in *Network, out *v1beta1.Netwo
 
 func autoConvert_v1beta1_Network_To_v1alpha3_Network(in *v1beta1.Network, out *Network, s conversion.Scope) error {
 	out.DNSName = in.DNSName
+	// WARNING: in.AttachedIPs requires manual conversion: does not exist in peer-type
 	return nil
 }
-
-// Convert_v1beta1_Network_To_v1alpha3_Network is an autogenerated conversion function.
-func Convert_v1beta1_Network_To_v1alpha3_Network(in *v1beta1.Network, out *Network, s conversion.Scope) error {
-	return autoConvert_v1beta1_Network_To_v1alpha3_Network(in, out, s)
-}
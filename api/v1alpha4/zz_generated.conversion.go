@@ -74,21 +74,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.MaasClusterSpec)(nil), (*MaasClusterSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_MaasClusterSpec_To_v1alpha4_MaasClusterSpec(a.(*v1beta1.MaasClusterSpec), b.(*MaasClusterSpec), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*MaasClusterStatus)(nil), (*v1beta1.MaasClusterStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha4_MaasClusterStatus_To_v1beta1_MaasClusterStatus(a.(*MaasClusterStatus), b.(*v1beta1.MaasClusterStatus), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.MaasClusterStatus)(nil), (*MaasClusterStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_MaasClusterStatus_To_v1alpha4_MaasClusterStatus(a.(*v1beta1.MaasClusterStatus), b.(*MaasClusterStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*MaasMachine)(nil), (*v1beta1.MaasMachine)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha4_MaasMachine_To_v1beta1_MaasMachine(a.(*MaasMachine), b.(*v1beta1.MaasMachine), scope)
 	}); err != nil {
@@ -114,11 +104,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.MaasMachineSpec)(nil), (*MaasMachineSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_MaasMachineSpec_To_v1alpha4_MaasMachineSpec(a.(*v1beta1.MaasMachineSpec), b.(*MaasMachineSpec), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*MaasMachineStatus)(nil), (*v1beta1.MaasMachineStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha4_MaasMachineStatus_To_v1beta1_MaasMachineStatus(a.(*MaasMachineStatus), b.(*v1beta1.MaasMachineStatus), scope)
 	}); err != nil {
@@ -164,11 +149,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.MaasMachineTemplateSpec)(nil), (*MaasMachineTemplateSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_MaasMachineTemplateSpec_To_v1alpha4_MaasMachineTemplateSpec(a.(*v1beta1.MaasMachineTemplateSpec), b.(*MaasMachineTemplateSpec), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*Machine)(nil), (*v1beta1.Machine)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha4_Machine_To_v1beta1_Machine(a.(*Machine), b.(*v1beta1.Machine), scope)
 	}); err != nil {
@@ -184,7 +164,27 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.Network)(nil), (*Network)(nil), func(a, b interface{}, scope conversion.Scope) error {
+	if err := s.AddConversionFunc((*v1beta1.MaasClusterSpec)(nil), (*MaasClusterSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_MaasClusterSpec_To_v1alpha4_MaasClusterSpec(a.(*v1beta1.MaasClusterSpec), b.(*MaasClusterSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1beta1.MaasClusterStatus)(nil), (*MaasClusterStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_MaasClusterStatus_To_v1alpha4_MaasClusterStatus(a.(*v1beta1.MaasClusterStatus), b.(*MaasClusterStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1beta1.MaasMachineSpec)(nil), (*MaasMachineSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_MaasMachineSpec_To_v1alpha4_MaasMachineSpec(a.(*v1beta1.MaasMachineSpec), b.(*MaasMachineSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1beta1.MaasMachineTemplateSpec)(nil), (*MaasMachineTemplateSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_MaasMachineTemplateSpec_To_v1alpha4_MaasMachineTemplateSpec(a.(*v1beta1.MaasMachineTemplateSpec), b.(*MaasMachineTemplateSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1beta1.Network)(nil), (*Network)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_Network_To_v1alpha4_Network(a.(*v1beta1.Network), b.(*Network), scope)
 	}); err != nil {
 		return err
@@ -248,7 +248,17 @@ func Convert_v1beta1_MaasCluster_To_v1alpha4_MaasCluster(in *v1beta1.MaasCluster
 
 func autoConvert_v1alpha4_MaasClusterList_To_v1beta1_MaasClusterList(in *MaasClusterList, out *v1beta1.MaasClusterList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]v1beta1.MaasCluster)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]v1beta1.MaasCluster, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha4_MaasCluster_To_v1beta1_MaasCluster(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -259,7 +269,17 @@ func Convert_v1alpha4_MaasClusterList_To_v1beta1_MaasClusterList(in *MaasCluster
 
 func autoConvert_v1beta1_MaasClusterList_To_v1alpha4_MaasClusterList(in *v1beta1.MaasClusterList, out *MaasClusterList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]MaasCluster)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MaasCluster, len(*in))
+		for i := range *in {
+			if err := Convert_v1beta1_MaasCluster_To_v1alpha4_MaasCluster(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -288,14 +308,11 @@ func autoConvert_v1beta1_MaasClusterSpec_To_v1alpha4_MaasClusterSpec(in *v1beta1
 		return err
 	}
 	out.FailureDomains = *(*[]string)(unsafe.Pointer(&in.FailureDomains))
+	// WARNING: in.AutoDiscoverFailureDomains requires manual conversion: does not exist in peer-type
+	// WARNING: in.ProviderIDFormat requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta1_MaasClusterSpec_To_v1alpha4_MaasClusterSpec is an autogenerated conversion function.
-func Convert_v1beta1_MaasClusterSpec_To_v1alpha4_MaasClusterSpec(in *v1beta1.MaasClusterSpec, out *MaasClusterSpec, s conversion.Scope) error {
-	return autoConvert_v1beta1_MaasClusterSpec_To_v1alpha4_MaasClusterSpec(in, out, s)
-}
-
 func autoConvert_v1alpha4_MaasClusterStatus_To_v1beta1_MaasClusterStatus(in *MaasClusterStatus, out *v1beta1.MaasClusterStatus, s conversion.Scope) error {
 	out.Ready = in.Ready
 	if err := Convert_v1alpha4_Network_To_v1beta1_Network(&in.Network, &out.Network, s); err != nil {
@@ -318,14 +335,10 @@ func autoConvert_v1beta1_MaasClusterStatus_To_v1alpha4_MaasClusterStatus(in *v1b
 	}
 	out.FailureDomains = *(*apiv1alpha4.FailureDomains)(unsafe.Pointer(&in.FailureDomains))
 	out.Conditions = *(*apiv1alpha4.Conditions)(unsafe.Pointer(&in.Conditions))
+	// WARNING: in.Capacity requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta1_MaasClusterStatus_To_v1alpha4_MaasClusterStatus is an autogenerated conversion function.
-func Convert_v1beta1_MaasClusterStatus_To_v1alpha4_MaasClusterStatus(in *v1beta1.MaasClusterStatus, out *MaasClusterStatus, s conversion.Scope) error {
-	return autoConvert_v1beta1_MaasClusterStatus_To_v1alpha4_MaasClusterStatus(in, out, s)
-}
-
 func autoConvert_v1alpha4_MaasMachine_To_v1beta1_MaasMachine(in *MaasMachine, out *v1beta1.MaasMachine, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha4_MaasMachineSpec_To_v1beta1_MaasMachineSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -360,7 +373,17 @@ func Convert_v1beta1_MaasMachine_To_v1alpha4_MaasMachine(in *v1beta1.MaasMachine
 
 func autoConvert_v1alpha4_MaasMachineList_To_v1beta1_MaasMachineList(in *MaasMachineList, out *v1beta1.MaasMachineList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]v1beta1.MaasMachine)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]v1beta1.MaasMachine, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha4_MaasMachine_To_v1beta1_MaasMachine(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -371,7 +394,17 @@ func Convert_v1alpha4_MaasMachineList_To_v1beta1_MaasMachineList(in *MaasMachine
 
 func autoConvert_v1beta1_MaasMachineList_To_v1alpha4_MaasMachineList(in *v1beta1.MaasMachineList, out *MaasMachineList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]MaasMachine)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MaasMachine, len(*in))
+		for i := range *in {
+			if err := Convert_v1beta1_MaasMachine_To_v1alpha4_MaasMachine(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -404,14 +437,13 @@ func autoConvert_v1beta1_MaasMachineSpec_To_v1alpha4_MaasMachineSpec(in *v1beta1
 	out.MinCPU = (*int)(unsafe.Pointer(in.MinCPU))
 	out.MinMemoryInMB = (*int)(unsafe.Pointer(in.MinMemoryInMB))
 	out.Image = in.Image
+	// WARNING: in.Tags requires manual conversion: does not exist in peer-type
+	// WARNING: in.ReleasePolicy requires manual conversion: does not exist in peer-type
+	// WARNING: in.ManagePower requires manual conversion: does not exist in peer-type
+	// WARNING: in.NodeLabelPrefixes requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta1_MaasMachineSpec_To_v1alpha4_MaasMachineSpec is an autogenerated conversion function.
-func Convert_v1beta1_MaasMachineSpec_To_v1alpha4_MaasMachineSpec(in *v1beta1.MaasMachineSpec, out *MaasMachineSpec, s conversion.Scope) error {
-	return autoConvert_v1beta1_MaasMachineSpec_To_v1alpha4_MaasMachineSpec(in, out, s)
-}
-
 func autoConvert_v1alpha4_MaasMachineStatus_To_v1beta1_MaasMachineStatus(in *MaasMachineStatus, out *v1beta1.MaasMachineStatus, s conversion.Scope) error {
 	out.Ready = in.Ready
 	out.MachineState = (*v1beta1.MachineState)(unsafe.Pointer(in.MachineState))
@@ -476,7 +508,17 @@ func Convert_v1beta1_MaasMachineTemplate_To_v1alpha4_MaasMachineTemplate(in *v1b
 
 func autoConvert_v1alpha4_MaasMachineTemplateList_To_v1beta1_MaasMachineTemplateList(in *MaasMachineTemplateList, out *v1beta1.MaasMachineTemplateList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]v1beta1.MaasMachineTemplate)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]v1beta1.MaasMachineTemplate, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha4_MaasMachineTemplate_To_v1beta1_MaasMachineTemplate(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -487,7 +529,17 @@ func Convert_v1alpha4_MaasMachineTemplateList_To_v1beta1_MaasMachineTemplateList
 
 func autoConvert_v1beta1_MaasMachineTemplateList_To_v1alpha4_MaasMachineTemplateList(in *v1beta1.MaasMachineTemplateList, out *MaasMachineTemplateList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]MaasMachineTemplate)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MaasMachineTemplate, len(*in))
+		for i := range *in {
+			if err := Convert_v1beta1_MaasMachineTemplate_To_v1alpha4_MaasMachineTemplate(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -536,14 +588,10 @@ func autoConvert_v1beta1_MaasMachineTemplateSpec_To_v1alpha4_MaasMachineTemplate
 	if err := Convert_v1beta1_MaasMachineTemplateResource_To_v1alpha4_MaasMachineTemplateResource(&in.Template, &out.Template, s); err != nil {
 		return err
 	}
+	// WARNING: in.Defaults requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta1_MaasMachineTemplateSpec_To_v1alpha4_MaasMachineTemplateSpec is an autogenerated conversion function.
-func Convert_v1beta1_MaasMachineTemplateSpec_To_v1alpha4_MaasMachineTemplateSpec(in *v1beta1.MaasMachineTemplateSpec, out *MaasMachineTemplateSpec, s conversion.Scope) error {
-	return autoConvert_v1beta1_MaasMachineTemplateSpec_To_v1alpha4_MaasMachineTemplateSpec(in, out, s)
-}
-
 func autoConvert_v1alpha4_Machine_To_v1beta1_Machine(in *Machine, out *v1beta1.Machine, s conversion.Scope) error {
 	out.ID = in.ID
 	out.Hostname = in.Hostname
@@ -586,10 +634,6 @@ func Convert_v1alpha4_Network_To_v1beta1_Network(in *Network, out *v1beta1.Netwo
 
 func autoConvert_v1beta1_Network_To_v1alpha4_Network(in *v1beta1.Network, out *Network, s conversion.Scope) error {
 	out.DNSName = in.DNSName
+	// WARNING: in.AttachedIPs requires manual conversion: does not exist in peer-type
 	return nil
 }
-
-// Convert_v1beta1_Network_To_v1alpha4_Network is an autogenerated conversion function.
-func Convert_v1beta1_Network_To_v1alpha4_Network(in *v1beta1.Network, out *Network, s conversion.Scope) error {
-	return autoConvert_v1beta1_Network_To_v1alpha4_Network(in, out, s)
-}
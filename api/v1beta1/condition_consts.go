@@ -53,6 +53,18 @@ const (
 
 	// MachineDeployStartedReason (Severity=Info) documents a MachineMachine controller started deploying
 	MachineDeployStartedReason = "MachineDeployStartedReason"
+
+	// MachineRedeployingReason (Severity=Info) documents a MaaS machine released to be redeployed
+	// because of the force redeploy annotation
+	MachineRedeployingReason = "MachineRedeploying"
+
+	// MachineNoCapacityReason (Severity=Warning) documents a MachineMachine waiting for a MaaS machine
+	// to be available for allocation before starting to deploy
+	MachineNoCapacityReason = "NoCapacity"
+
+	// ReadOnlyModeReason (Severity=Info) documents a MaasMachine not deployed, or a MaasCluster DNS resource not created,
+	// because the controller runs in read-only mode
+	ReadOnlyModeReason = "ReadOnlyMode"
 )
 
 const (
@@ -77,6 +89,18 @@ const (
 	WaitForDNSNameReason = "WaitForDNSName"
 )
 
+const (
+	// FailureDomainsValidCondition documents whether all configured failure domains match an existing MaaS zone.
+	// It's informational and not part of the MaasCluster Ready summary
+	FailureDomainsValidCondition clusterv1.ConditionType = "FailureDomainsValid"
+
+	// FailureDomainNotFoundReason (Severity=Warning) documents a configured failure domain without a matching MaaS zone
+	FailureDomainNotFoundReason = "FailureDomainNotFound"
+
+	// ZoneLookupFailedReason (Severity=Warning) documents a failure listing the MaaS zones; will be retried
+	ZoneLookupFailedReason = "ZoneLookupFailed"
+)
+
 const (
 	// APIServerAvailableCondition documents whether API server is reachable
 	APIServerAvailableCondition clusterv1.ConditionType = "APIServerAvailable"
@@ -41,8 +41,31 @@ type MaasClusterSpec struct {
 	// but useful for MaaS since we can limit the domains to these
 	// +optional
 	FailureDomains []string `json:"failureDomains,omitempty"`
+
+	// AutoDiscoverFailureDomains populates the status failure domains from the MaaS zones
+	// when no FailureDomains are set on the spec
+	// +optional
+	AutoDiscoverFailureDomains bool `json:"autoDiscoverFailureDomains,omitempty"`
+
+	// ProviderIDFormat is the format of the providerID set on the machines (and nodes) of the cluster.
+	// Zone (default) is maas:///<zone>/<system-id>, SystemID is maas://<system-id>
+	// +kubebuilder:default=Zone
+	// +optional
+	ProviderIDFormat ProviderIDFormat `json:"providerIDFormat,omitempty"`
 }
 
+// ProviderIDFormat defines how the providerID of a machine is built
+// +kubebuilder:validation:Enum=Zone;SystemID
+type ProviderIDFormat string
+
+const (
+	// ProviderIDFormatZone includes the zone in the providerID, maas:///<zone>/<system-id>
+	ProviderIDFormatZone ProviderIDFormat = "Zone"
+
+	// ProviderIDFormatSystemID only uses the system id in the providerID, maas://<system-id>
+	ProviderIDFormatSystemID ProviderIDFormat = "SystemID"
+)
+
 // MaasClusterStatus defines the observed state of MaasCluster
 type MaasClusterStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
@@ -61,12 +84,42 @@ type MaasClusterStatus struct {
 	// Conditions defines current service state of the MaasCluster.
 	// +optional
 	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+
+	// Capacity summarizes the MaaS machines in the Ready state per failure domain. The counts ignore
+	// the resource pool, tags, CPU and memory constraints of the machines, so they're an upper bound
+	// of the machines which can be allocated
+	// +optional
+	Capacity *Capacity `json:"capacity,omitempty"`
+}
+
+// Capacity summarizes the MaaS machines in the Ready state, regardless of the allocation constraints
+type Capacity struct {
+	// LastUpdated is the last time the capacity was refreshed from MaaS
+	LastUpdated metav1.Time `json:"lastUpdated"`
+
+	// FailureDomains lists the Ready machines per failure domain
+	// +optional
+	FailureDomains []FailureDomainCapacity `json:"failureDomains,omitempty"`
+}
+
+// FailureDomainCapacity is the number of MaaS machines in the Ready state in a failure domain
+type FailureDomainCapacity struct {
+	// Name is the failure domain (MaaS zone) name
+	Name string `json:"name"`
+
+	// ReadyMachines is the raw number of machines in the Ready state in the zone, in any resource pool
+	// and with any tags, CPU and memory
+	ReadyMachines int `json:"readyMachines"`
 }
 
 // Network encapsulates the Cluster Network
 type Network struct {
 	// DNSName is the Kubernetes api server name
 	DNSName string `json:"dnsName,omitempty"`
+
+	// AttachedIPs are the control plane machine IPs currently attached to the DNS resource
+	// +optional
+	AttachedIPs []string `json:"attachedIPs,omitempty"`
 }
 
 // APIEndpoint represents a reachable Kubernetes API endpoint.
@@ -88,6 +141,10 @@ func (in APIEndpoint) IsZero() bool {
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".metadata.labels.cluster\\.x-k8s\\.io/cluster-name",description="Cluster to which this MaasCluster belongs"
+// +kubebuilder:printcolumn:name="Ready",type="boolean",JSONPath=".status.ready",description="Cluster infrastructure is ready for MaaS instances"
+// +kubebuilder:printcolumn:name="DNSName",type="string",JSONPath=".status.network.dnsName",description="DNS name of the API server"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of MaasCluster"
 
 // MaasCluster is the Schema for the maasclusters API
 type MaasCluster struct {
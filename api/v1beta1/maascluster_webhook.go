@@ -18,9 +18,11 @@ package v1beta1
 
 import (
 	"fmt"
+	"strings"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -51,7 +53,8 @@ func (r *MaasCluster) Default() {
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
 func (r *MaasCluster) ValidateCreate() error {
 	maasclusterlog.Info("validate create", "name", r.Name)
-	return nil
+
+	return validateDNSDomain(r.Spec.DNSDomain)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
@@ -65,6 +68,18 @@ func (r *MaasCluster) ValidateUpdate(old runtime.Object) error {
 	if r.Spec.DNSDomain != oldC.Spec.DNSDomain {
 		return apierrors.NewBadRequest("changing cluster DNS Domain not allowed")
 	}
+
+	if r.Spec.ProviderIDFormat != oldC.Spec.ProviderIDFormat {
+		return apierrors.NewBadRequest("changing cluster providerID format not allowed")
+	}
+
+	if !oldC.Spec.ControlPlaneEndpoint.IsZero() && r.Spec.ControlPlaneEndpoint != oldC.Spec.ControlPlaneEndpoint {
+		return apierrors.NewBadRequest(fmt.Sprintf("changing cluster control plane endpoint not allowed, old=%s:%d, new=%s:%d",
+			oldC.Spec.ControlPlaneEndpoint.Host, oldC.Spec.ControlPlaneEndpoint.Port, r.Spec.ControlPlaneEndpoint.Host, r.Spec.ControlPlaneEndpoint.Port))
+	}
+
+	// The DNS domain format is only validated on create: it is immutable, and clusters created
+	// before the validation (e.g. with a trailing dot) still have to be updated and deleted
 	return nil
 }
 
@@ -73,3 +88,16 @@ func (r *MaasCluster) ValidateDelete() error {
 	maasclusterlog.Info("validate delete", "name", r.Name)
 	return nil
 }
+
+// validateDNSDomain ensures the domain can be safely used to build the MaaS DNS resource name
+func validateDNSDomain(domain string) error {
+	if strings.IndexFunc(domain, func(c rune) bool { return c <= ' ' || c == 0x7f }) >= 0 {
+		return apierrors.NewBadRequest(fmt.Sprintf("maas cluster dns domain %q must not contain whitespace or control characters", domain))
+	}
+
+	if errs := validation.IsDNS1123Subdomain(domain); len(errs) > 0 {
+		return apierrors.NewBadRequest(fmt.Sprintf("maas cluster dns domain %q is invalid: %s", domain, strings.Join(errs, ", ")))
+	}
+
+	return nil
+}
@@ -38,6 +38,26 @@ func TestMaasCluster_ValidateCreate(t *testing.T) {
 			dnsDomain: "",
 			wantError: true,
 		},
+		{
+			name:      "should not allow creation with trailing dot in dns name",
+			dnsDomain: "maas.sc.",
+			wantError: true,
+		},
+		{
+			name:      "should not allow creation with whitespace in dns name",
+			dnsDomain: "maas .sc",
+			wantError: true,
+		},
+		{
+			name:      "should not allow creation with newline in dns name",
+			dnsDomain: "maas.sc\n",
+			wantError: true,
+		},
+		{
+			name:      "should not allow creation with invalid dns label",
+			dnsDomain: "-maas.sc",
+			wantError: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -85,6 +105,53 @@ func TestMAASCluster_Update(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "change in providerIDFormat should not be allowed",
+			oldCluster: &MaasCluster{
+				Spec: MaasClusterSpec{
+					DNSDomain:        "maas.sc",
+					ProviderIDFormat: ProviderIDFormatZone,
+				},
+			},
+			newCluster: &MaasCluster{
+				Spec: MaasClusterSpec{
+					DNSDomain:        "maas.sc",
+					ProviderIDFormat: ProviderIDFormatSystemID,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "setting the control plane endpoint should be allowed",
+			oldCluster: &MaasCluster{
+				Spec: MaasClusterSpec{
+					DNSDomain: "maas.sc",
+				},
+			},
+			newCluster: &MaasCluster{
+				Spec: MaasClusterSpec{
+					DNSDomain:            "maas.sc",
+					ControlPlaneEndpoint: APIEndpoint{Host: "a-abcde.maas.sc", Port: 6443},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "change in control plane endpoint should not be allowed",
+			oldCluster: &MaasCluster{
+				Spec: MaasClusterSpec{
+					DNSDomain:            "maas.sc",
+					ControlPlaneEndpoint: APIEndpoint{Host: "a-abcde.maas.sc", Port: 6443},
+				},
+			},
+			newCluster: &MaasCluster{
+				Spec: MaasClusterSpec{
+					DNSDomain:            "maas.sc",
+					ControlPlaneEndpoint: APIEndpoint{Host: "a-fghij.maas.sc", Port: 6443},
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		ctx := context.TODO()
@@ -104,3 +171,19 @@ func TestMAASCluster_Update(t *testing.T) {
 		})
 	}
 }
+
+func TestMAASCluster_UpdateLegacyDNSDomain(t *testing.T) {
+	// Clusters created before the dns domain validation may have a trailing dot, they must stay updatable
+	oldCluster := &MaasCluster{
+		Spec: MaasClusterSpec{
+			DNSDomain: "maas.sc.",
+		},
+	}
+	newCluster := oldCluster.DeepCopy()
+	newCluster.Finalizers = nil
+	newCluster.Spec.ControlPlaneEndpoint = APIEndpoint{Host: "a-abcde.maas.sc.", Port: 6443}
+
+	if err := newCluster.ValidateUpdate(oldCluster); err != nil {
+		t.Errorf("ValidateUpdate() error = %v, wantErr false", err)
+	}
+}
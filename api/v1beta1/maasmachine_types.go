@@ -26,6 +26,44 @@ const (
 	// MachineFinalizer allows MaasMachineReconciler to clean up resources associated with MaasMachine before
 	// removing it from the apiserver.
 	MachineFinalizer = "maasmachine.infrastructure.cluster.x-k8s.io"
+
+	// NodeZoneLabel is the workload cluster node label set to the MaaS zone of the machine
+	NodeZoneLabel = "maas.io/zone"
+
+	// NodeResourcePoolLabel is the workload cluster node label set to the MaaS resource pool of the machine
+	NodeResourcePoolLabel = "maas.io/resource-pool"
+
+	// NodeTagLabelPrefix prefixes the workload cluster node labels added for each MaaS tag of the machine
+	NodeTagLabelPrefix = "maas.io/tag-"
+
+	// NodeSystemIDAnnotation is the workload cluster node annotation set to the MaaS system id of the machine
+	NodeSystemIDAnnotation = "maas.io/system-id"
+
+	// NodeManagedLabelsAnnotation is the workload cluster node annotation listing the comma separated keys of
+	// the labels set by the provider, so they're removed once they no longer apply
+	NodeManagedLabelsAnnotation = "maas.io/managed-labels"
+
+	// ForceRedeployAnnotation on a MaasMachine drains the node, then releases and redeploys its deployed MaaS machine once.
+	// The annotation is removed when the redeploy starts, and ignored on control plane machines.
+	// The released machine stays in the MaaS Ready pool until it's allocated again, so another MaaS user may take it.
+	// The machine is redeployed with its existing bootstrap data; the bootstrap provider stops refreshing its join token
+	// once the node joined, so the annotation is removed without a redeploy when that token is expired.
+	// It's kept in read-only mode until the mode is turned off.
+	ForceRedeployAnnotation = "maas.spectrocloud.com/force-redeploy"
+)
+
+// ReleasePolicy defines what happens to the MaaS machine when the MaasMachine is deleted
+// +kubebuilder:validation:Enum=Release;Retain
+type ReleasePolicy string
+
+const (
+	// ReleasePolicyRelease releases the MaaS machine back to the pool on delete
+	ReleasePolicyRelease ReleasePolicy = "Release"
+
+	// ReleasePolicyRetain leaves the MaaS machine allocated, deployed and powered on delete. The provider never
+	// reuses it, it has to be released in MaaS to go back to the pool. Its kubelet keeps running and registers
+	// a new, schedulable Node in the workload cluster
+	ReleasePolicyRetain ReleasePolicy = "Retain"
 )
 
 // MaasMachineSpec defines the desired state of MaasMachine
@@ -63,6 +101,29 @@ type MaasMachineSpec struct {
 	// Image will be the MaaS image id
 	// +kubebuilder:validation:MinLength=1
 	Image string `json:"image"`
+
+	// ReleasePolicy defines whether the MaaS machine is released (default) or retained on delete.
+	// A retained machine stays allocated to the MaaS user and keeps running the deployed image.
+	// Its Node is cordoned on delete, but its kubelet registers a new, schedulable Node once the
+	// Machine controller deleted the old one; the provider can't power the machine off, so cordon
+	// or delete that Node and release or redeploy the machine in MaaS. It's never reused for
+	// another MaasMachine since only Ready machines are allocated.
+	// Retain is not allowed on control plane machines.
+	// +optional
+	ReleasePolicy ReleasePolicy `json:"releasePolicy,omitempty"`
+
+	// ManagePower powers on deployed machines found powered off (default).
+	// When false the powered off state is only reported on the MachineDeployed condition.
+	// +kubebuilder:default=true
+	// +optional
+	ManagePower *bool `json:"managePower,omitempty"`
+
+	// NodeLabelPrefixes lists the prefixes of the MaaS zone (maas.io/zone), resource pool (maas.io/resource-pool)
+	// and tag (maas.io/tag-<tag>) labels set on the workload cluster node once it's registered, e.g. maas.io/
+	// for all of them. The system id is set as annotation when any prefix is given.
+	// Labels set by the provider which no longer apply are removed from the node.
+	// +optional
+	NodeLabelPrefixes []string `json:"nodeLabelPrefixes,omitempty"`
 }
 
 // MaasMachineStatus defines the observed state of MaasMachine
@@ -105,6 +166,12 @@ type MaasMachineStatus struct {
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".metadata.labels.cluster\\.x-k8s\\.io/cluster-name",description="Cluster to which this MaasMachine belongs"
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.machineState",description="MaaS machine state"
+// +kubebuilder:printcolumn:name="Powered",type="boolean",JSONPath=".status.machinePowered",description="MaaS machine power state"
+// +kubebuilder:printcolumn:name="AvailabilityZone",type="string",JSONPath=".spec.failureDomain",description="MaaS zone the machine is deployed in"
+// +kubebuilder:printcolumn:name="ProviderID",type="string",JSONPath=".spec.providerID",description="MaaS providerID"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of MaasMachine"
 
 // MaasMachine is the Schema for the maasmachines API
 type MaasMachine struct {
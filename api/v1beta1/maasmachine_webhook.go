@@ -21,6 +21,7 @@ import (
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -51,7 +52,7 @@ func (r *MaasMachine) Default() {
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
 func (r *MaasMachine) ValidateCreate() error {
 	maasmachinelog.Info("validate create", "name", r.Name)
-	return nil
+	return r.validateReleasePolicy()
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -76,5 +77,13 @@ func (r *MaasMachine) ValidateUpdate(old runtime.Object) error {
 	if *r.Spec.MinMemoryInMB != *oldM.Spec.MinMemoryInMB {
 		return apierrors.NewBadRequest(fmt.Sprintf("maas machine min memory change is not allowed, old=%d MB, new=%d MB", oldM.Spec.MinMemoryInMB, r.Spec.MinMemoryInMB))
 	}
+	return r.validateReleasePolicy()
+}
+
+// validateReleasePolicy rejects retaining control plane machines, which would keep running as cluster members
+func (r *MaasMachine) validateReleasePolicy() error {
+	if _, ok := r.Labels[clusterv1.MachineControlPlaneLabelName]; ok && r.Spec.ReleasePolicy == ReleasePolicyRetain {
+		return apierrors.NewBadRequest(fmt.Sprintf("maas machine release policy %s is not allowed on control plane machines", ReleasePolicyRetain))
+	}
 	return nil
 }
@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
 func TestMaasMachine_ValidateUpdate(t *testing.T) {
@@ -107,3 +108,41 @@ func TestMaasMachine_ValidateUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestMaasMachine_ValidateReleasePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		labels  map[string]string
+		policy  ReleasePolicy
+		wantErr bool
+	}{
+		{
+			name:    "retain on worker machine should be allowed",
+			policy:  ReleasePolicyRetain,
+			wantErr: false,
+		},
+		{
+			name:    "release on control plane machine should be allowed",
+			labels:  map[string]string{clusterv1.MachineControlPlaneLabelName: ""},
+			policy:  ReleasePolicyRelease,
+			wantErr: false,
+		},
+		{
+			name:    "retain on control plane machine should not be allowed",
+			labels:  map[string]string{clusterv1.MachineControlPlaneLabelName: ""},
+			policy:  ReleasePolicyRetain,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			machine := &MaasMachine{
+				ObjectMeta: metav1.ObjectMeta{Labels: tt.labels},
+				Spec:       MaasMachineSpec{ReleasePolicy: tt.policy},
+			}
+			if err := machine.ValidateCreate(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
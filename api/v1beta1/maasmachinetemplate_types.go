@@ -23,6 +23,30 @@ import (
 // MaasMachineTemplateSpec defines the desired state of MaasMachineTemplate
 type MaasMachineTemplateSpec struct {
 	Template MaasMachineTemplateResource `json:"template"`
+
+	// Defaults are merged into the spec of each MaasMachine cloned from the template before it's deployed.
+	// Unlike template.spec, which is copied as is into every MaasMachine, the failure domain and resource pool
+	// defaults are fallbacks, e.g. the failure domain default doesn't override the failure domains spread by KCP
+	// or set on a MachineDeployment. Defaults are immutable since they're read when a MaasMachine is first deployed.
+	// +optional
+	Defaults *MaasMachineTemplateDefaults `json:"defaults,omitempty"`
+}
+
+// MaasMachineTemplateDefaults are placement defaults inherited by the MaasMachines cloned from a template.
+// Tags are appended to the machine tags. The failure domain and resource pool are only used when neither
+// the MaasMachine nor its Machine set one.
+type MaasMachineTemplateDefaults struct {
+	// FailureDomain is the default MaaS zone of the machines
+	// +optional
+	FailureDomain *string `json:"failureDomain,omitempty"`
+
+	// ResourcePool is the default MaaS resource pool of the machines
+	// +optional
+	ResourcePool *string `json:"resourcePool,omitempty"`
+
+	// Tags are appended to the placement tags of the machines
+	// +optional
+	Tags []string `json:"tags,omitempty"`
 }
 
 // MaasMachineTemplateResource describes the data needed to create a MaasMachine from a template
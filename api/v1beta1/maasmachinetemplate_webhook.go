@@ -19,6 +19,7 @@ package v1beta1
 import (
 	"fmt"
 
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -70,6 +71,10 @@ func (r *MaasMachineTemplate) ValidateUpdate(old runtime.Object) error {
 	if *r.Spec.Template.Spec.MinMemoryInMB != *oldM.Spec.Template.Spec.MinMemoryInMB {
 		return apierrors.NewBadRequest(fmt.Sprintf("maas machine template min memory change is not allowed, old=%d MB, new=%d MB", oldM.Spec.Template.Spec.MinMemoryInMB, r.Spec.Template.Spec.MinMemoryInMB))
 	}
+
+	if !apiequality.Semantic.DeepEqual(r.Spec.Defaults, oldM.Spec.Defaults) {
+		return apierrors.NewBadRequest("maas machine template defaults change is not allowed")
+	}
 	return nil
 }
 
@@ -111,6 +111,38 @@ func TestMaasMachineTemplate_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "change in defaults should not be allowed",
+			oldMachineTemplate: &MaasMachineTemplate{
+				Spec: MaasMachineTemplateSpec{
+					Template: MaasMachineTemplateResource{
+						Spec: MaasMachineSpec{
+							MinCPU:        &cpuBefore,
+							MinMemoryInMB: &memoryBefore,
+							Image:         "ubuntu1804-k8s-1.19",
+						},
+					},
+					Defaults: &MaasMachineTemplateDefaults{
+						Tags: []string{"a"},
+					},
+				},
+			},
+			newMachineTemplate: &MaasMachineTemplate{
+				Spec: MaasMachineTemplateSpec{
+					Template: MaasMachineTemplateResource{
+						Spec: MaasMachineSpec{
+							MinCPU:        &cpuBefore,
+							MinMemoryInMB: &memoryBefore,
+							Image:         "ubuntu1804-k8s-1.19",
+						},
+					},
+					Defaults: &MaasMachineTemplateDefaults{
+						Tags: []string{"a", "b"},
+					},
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		ctx := context.TODO()
@@ -42,6 +42,42 @@ func (in *APIEndpoint) DeepCopy() *APIEndpoint {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Capacity) DeepCopyInto(out *Capacity) {
+	*out = *in
+	in.LastUpdated.DeepCopyInto(&out.LastUpdated)
+	if in.FailureDomains != nil {
+		in, out := &in.FailureDomains, &out.FailureDomains
+		*out = make([]FailureDomainCapacity, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Capacity.
+func (in *Capacity) DeepCopy() *Capacity {
+	if in == nil {
+		return nil
+	}
+	out := new(Capacity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailureDomainCapacity) DeepCopyInto(out *FailureDomainCapacity) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailureDomainCapacity.
+func (in *FailureDomainCapacity) DeepCopy() *FailureDomainCapacity {
+	if in == nil {
+		return nil
+	}
+	out := new(FailureDomainCapacity)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MaasCluster) DeepCopyInto(out *MaasCluster) {
 	*out = *in
@@ -125,7 +161,7 @@ func (in *MaasClusterSpec) DeepCopy() *MaasClusterSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MaasClusterStatus) DeepCopyInto(out *MaasClusterStatus) {
 	*out = *in
-	out.Network = in.Network
+	in.Network.DeepCopyInto(&out.Network)
 	if in.FailureDomains != nil {
 		in, out := &in.FailureDomains, &out.FailureDomains
 		*out = make(apiv1beta1.FailureDomains, len(*in))
@@ -140,6 +176,11 @@ func (in *MaasClusterStatus) DeepCopyInto(out *MaasClusterStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		*out = new(Capacity)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaasClusterStatus.
@@ -249,6 +290,16 @@ func (in *MaasMachineSpec) DeepCopyInto(out *MaasMachineSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ManagePower != nil {
+		in, out := &in.ManagePower, &out.ManagePower
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NodeLabelPrefixes != nil {
+		in, out := &in.NodeLabelPrefixes, &out.NodeLabelPrefixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaasMachineSpec.
@@ -334,6 +385,36 @@ func (in *MaasMachineTemplate) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaasMachineTemplateDefaults) DeepCopyInto(out *MaasMachineTemplateDefaults) {
+	*out = *in
+	if in.FailureDomain != nil {
+		in, out := &in.FailureDomain, &out.FailureDomain
+		*out = new(string)
+		**out = **in
+	}
+	if in.ResourcePool != nil {
+		in, out := &in.ResourcePool, &out.ResourcePool
+		*out = new(string)
+		**out = **in
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaasMachineTemplateDefaults.
+func (in *MaasMachineTemplateDefaults) DeepCopy() *MaasMachineTemplateDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(MaasMachineTemplateDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MaasMachineTemplateList) DeepCopyInto(out *MaasMachineTemplateList) {
 	*out = *in
@@ -386,6 +467,11 @@ func (in *MaasMachineTemplateResource) DeepCopy() *MaasMachineTemplateResource {
 func (in *MaasMachineTemplateSpec) DeepCopyInto(out *MaasMachineTemplateSpec) {
 	*out = *in
 	in.Template.DeepCopyInto(&out.Template)
+	if in.Defaults != nil {
+		in, out := &in.Defaults, &out.Defaults
+		*out = new(MaasMachineTemplateDefaults)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaasMachineTemplateSpec.
@@ -421,6 +507,11 @@ func (in *Machine) DeepCopy() *Machine {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Network) DeepCopyInto(out *Network) {
 	*out = *in
+	if in.AttachedIPs != nil {
+		in, out := &in.AttachedIPs, &out.AttachedIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Network.
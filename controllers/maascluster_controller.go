@@ -20,11 +20,14 @@ import (
 	"context"
 	"fmt"
 	"k8s.io/apimachinery/pkg/runtime"
+	"sort"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -34,6 +37,7 @@ import (
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -43,9 +47,13 @@ import (
 	infrav1beta1 "github.com/spectrocloud/cluster-api-provider-maas/api/v1beta1"
 	"github.com/spectrocloud/cluster-api-provider-maas/pkg/maas/dns"
 	"github.com/spectrocloud/cluster-api-provider-maas/pkg/maas/scope"
+	"github.com/spectrocloud/cluster-api-provider-maas/pkg/maas/zone"
 	infrautil "github.com/spectrocloud/cluster-api-provider-maas/pkg/util"
 )
 
+// CapacityRefreshInterval is the minimum interval between two MaaS capacity lookups of a cluster
+const CapacityRefreshInterval = 5 * time.Minute
+
 // MaasClusterReconciler reconciles a MaasCluster object
 type MaasClusterReconciler struct {
 	client.Client
@@ -54,6 +62,13 @@ type MaasClusterReconciler struct {
 	Recorder            record.EventRecorder
 	GenericEventChannel chan event.GenericEvent
 	Tracker             *remote.ClusterCacheTracker
+
+	// ReadOnly reconciles and reports status without making any change to MaaS
+	ReadOnly bool
+
+	// APIServerHealthCheckTimeout is the timeout of the API server probe done before a control plane
+	// machine IP is attached to DNS. The probe is disabled when 0.
+	APIServerHealthCheckTimeout time.Duration
 }
 
 //+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=maasclusters,verbs=get;list;watch;create;update;patch;delete
@@ -91,6 +106,7 @@ func (r *MaasClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		ClusterEventChannel: r.GenericEventChannel,
 		ControllerName:      "maascluster",
 		Tracker:             r.Tracker,
+		ReadOnly:            r.ReadOnly,
 	})
 	if err != nil {
 		return reconcile.Result{}, errors.Errorf("failed to create scope: %+v", err)
@@ -104,15 +120,11 @@ func (r *MaasClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	}()
 
 	// Support FailureDomains
-	// In cloud providers this would likely look up which failure domains are supported and set the status appropriately.
-	// so kCP will distribute the CPs across multiple failure domains
-	failureDomains := make(clusterv1.FailureDomains)
-	for _, az := range maasCluster.Spec.FailureDomains {
-		failureDomains[az] = clusterv1.FailureDomainSpec{
-			ControlPlane: true,
-		}
+	// Explicit spec entries take precedence; otherwise the MaaS zones are looked up in reconcileNormal
+	// when auto-discovery is enabled
+	if len(maasCluster.Spec.FailureDomains) > 0 || !maasCluster.Spec.AutoDiscoverFailureDomains {
+		maasCluster.Status.FailureDomains = toFailureDomains(maasCluster.Spec.FailureDomains)
 	}
-	maasCluster.Status.FailureDomains = failureDomains
 
 	// Handle deleted clusters
 	if !maasCluster.DeletionTimestamp.IsZero() {
@@ -139,6 +151,18 @@ func (r *MaasClusterReconciler) reconcileDelete(ctx context.Context, clusterScop
 		return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
 	}
 
+	dnsName := maasCluster.Status.Network.DNSName
+	switch err := dns.NewService(clusterScope).DeleteDNS(); {
+	case errors.Is(err, scope.ErrReadOnly):
+		r.Recorder.Eventf(maasCluster, corev1.EventTypeWarning, "DNSLeftBehind", "Read-only mode; DNS resource %q is left in MaaS", dnsName)
+	case err != nil:
+		clusterScope.Error(err, "failed to delete DNS")
+		r.Recorder.Eventf(maasCluster, corev1.EventTypeWarning, "FailedDeleteDNS", "Failed to delete DNS resource %q: %v", dnsName, err)
+		return reconcile.Result{}, err
+	case dnsName != "":
+		r.Recorder.Eventf(maasCluster, corev1.EventTypeNormal, "SuccessfulDeleteDNS", "Deleted DNS resource %q", dnsName)
+	}
+
 	// Cluster is deleted so remove the finalizer.
 	controllerutil.RemoveFinalizer(maasCluster, infrav1beta1.ClusterFinalizer)
 
@@ -147,21 +171,24 @@ func (r *MaasClusterReconciler) reconcileDelete(ctx context.Context, clusterScop
 	return reconcile.Result{}, nil
 }
 
-func (r *MaasClusterReconciler) reconcileDNSAttachments(clusterScope *scope.ClusterScope, dnssvc *dns.Service) error {
+// reconcileDNSAttachments registers the running control plane machines with the API server DNS.
+// When it returns ErrRequeueDNS, pendingSince is the time the oldest pending attachment started waiting, if known.
+func (r *MaasClusterReconciler) reconcileDNSAttachments(clusterScope *scope.ClusterScope, dnssvc *dns.Service) (pendingSince *metav1.Time, _ error) {
 	machines, err := clusterScope.GetClusterMaasMachines()
 	if err != nil {
-		return errors.Wrapf(err, "Unable to list all maas machines")
+		return nil, errors.Wrapf(err, "Unable to list all maas machines")
 	}
 
 	var runningIpAddresses []string
 
 	currentIPs, err := dnssvc.GetAPIServerDNSRecords()
 	if err != nil {
-		return errors.Wrap(err, "Unable to get the dns resources")
+		return nil, errors.Wrap(err, "Unable to get the dns resources")
 	}
 
 	machinesPendingAttachment := make([]*infrav1beta1.MaasMachine, 0)
 	machinesPendingDetachment := make([]*infrav1beta1.MaasMachine, 0)
+	machinesPendingHealthCheck := make([]*infrav1beta1.MaasMachine, 0)
 
 	for _, m := range machines {
 		if !IsControlPlaneMachine(m) {
@@ -179,25 +206,142 @@ func (r *MaasClusterReconciler) reconcileDNSAttachments(clusterScope *scope.Clus
 			}
 		} else if IsRunning(m) {
 			if !attached {
+				if r.APIServerHealthCheckTimeout > 0 && !dnssvc.APIServerIsResponding(machineIP, r.APIServerHealthCheckTimeout) {
+					clusterScope.Info("Machine API server not responding yet; delaying DNS attachment", "machine", m.Name)
+					machinesPendingHealthCheck = append(machinesPendingHealthCheck, m)
+					continue
+				}
 				clusterScope.Info("Healthy machine without DNS attachment; attaching.", "machine", m.Name)
 				machinesPendingAttachment = append(machinesPendingAttachment, m)
 			}
 
 			runningIpAddresses = append(runningIpAddresses, machineIP)
 		}
-		//r.Recorder.Eventf(machineScope.MaasMachine, corev1.EventTypeNormal, "SuccessfulDetachControlPlaneDNS",
-		//	"Control plane instance %q is de-registered from load balancer", i.ID)
-		//runningIpAddresses = append(runningIpAddresses, m.)
 	}
 
-	if err := dnssvc.UpdateDNSAttachments(runningIpAddresses); err != nil {
-		return err
-	} else if len(machinesPendingAttachment) > 0 || len(machinesPendingDetachment) > 0 {
+	if err := dnssvc.UpdateDNSAttachments(runningIpAddresses); errors.Is(err, scope.ErrReadOnly) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	dnsName := clusterScope.GetDNSName()
+	for _, m := range machinesPendingAttachment {
+		r.Recorder.Eventf(m, corev1.EventTypeNormal, "SuccessfulAttachControlPlaneDNS",
+			"Control plane machine IP %q is registered with DNS resource %q", getExternalMachineIP(m), dnsName)
+	}
+	for _, m := range machinesPendingDetachment {
+		r.Recorder.Eventf(m, corev1.EventTypeNormal, "SuccessfulDetachControlPlaneDNS",
+			"Control plane machine IP %q is de-registered from DNS resource %q", getExternalMachineIP(m), dnsName)
+	}
+
+	if len(machinesPendingAttachment) > 0 || len(machinesPendingDetachment) > 0 || len(machinesPendingHealthCheck) > 0 {
 		clusterScope.Info("Pending DNS attachments or detachments; will retry again")
-		return ErrRequeueDNS
+		return earliestDNSAttachedTransition(append(machinesPendingAttachment, machinesPendingHealthCheck...)), ErrRequeueDNS
+	}
+
+	return nil, nil
+}
+
+// earliestDNSAttachedTransition returns the earliest DNSAttached condition transition of the machines, nil if none has one
+func earliestDNSAttachedTransition(machines []*infrav1beta1.MaasMachine) *metav1.Time {
+	var earliest *metav1.Time
+	for _, m := range machines {
+		if t := conditions.GetLastTransitionTime(m, infrav1beta1.DNSAttachedCondition); t != nil && (earliest == nil || t.Before(earliest)) {
+			earliest = t
+		}
+	}
+	return earliest
+}
+
+// reconcileFailureDomains reports configured failure domains which don't exist as MaaS zones.
+// A mismatch is only surfaced as a condition so machines in valid zones can still be deployed.
+func (r *MaasClusterReconciler) reconcileFailureDomains(clusterScope *scope.ClusterScope) {
+	maasCluster := clusterScope.MaasCluster
+	zoneService := zone.NewService(clusterScope)
+
+	if len(maasCluster.Spec.FailureDomains) == 0 && maasCluster.Spec.AutoDiscoverFailureDomains {
+		zones, err := zoneService.GetZoneNames()
+		if err != nil {
+			// Keep the previously discovered failure domains until MaaS can be queried again
+			clusterScope.Error(err, "failed to discover failure domains")
+			conditions.MarkFalse(maasCluster, infrav1beta1.FailureDomainsValidCondition, infrav1beta1.ZoneLookupFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+			return
+		}
+
+		maasCluster.Status.FailureDomains = toFailureDomains(zones.List())
+		conditions.MarkTrue(maasCluster, infrav1beta1.FailureDomainsValidCondition)
+		return
+	}
+
+	missing, err := zoneService.GetMissingFailureDomains()
+	if err != nil {
+		clusterScope.Error(err, "failed to validate failure domains")
+		conditions.MarkFalse(maasCluster, infrav1beta1.FailureDomainsValidCondition, infrav1beta1.ZoneLookupFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return
+	}
+
+	if len(missing) > 0 {
+		clusterScope.Info("Failure domains not found in MaaS zones", "failure-domains", missing)
+		conditions.MarkFalse(maasCluster, infrav1beta1.FailureDomainsValidCondition, infrav1beta1.FailureDomainNotFoundReason, clusterv1.ConditionSeverityWarning,
+			"failure domains %v do not match any MaaS zone", missing)
+		return
+	}
+
+	conditions.MarkTrue(maasCluster, infrav1beta1.FailureDomainsValidCondition)
+}
+
+// reconcileCapacity summarizes the machines available for allocation per failure domain.
+// The lookup lists all MaaS machines so it's throttled to CapacityRefreshInterval.
+func (r *MaasClusterReconciler) reconcileCapacity(clusterScope *scope.ClusterScope) {
+	maasCluster := clusterScope.MaasCluster
+
+	if c := maasCluster.Status.Capacity; c != nil && time.Since(c.LastUpdated.Time) < CapacityRefreshInterval {
+		return
+	}
+
+	counts, err := zone.NewService(clusterScope).GetReadyMachineCounts()
+	if err != nil {
+		// Informational only; retried on the next reconcile
+		clusterScope.Error(err, "failed to refresh capacity")
+		return
+	}
+
+	// Report the cluster failure domains, or every zone seen when none are set
+	names := make([]string, 0, len(maasCluster.Status.FailureDomains))
+	for fd := range maasCluster.Status.FailureDomains {
+		names = append(names, fd)
+	}
+	if len(names) == 0 {
+		for z := range counts {
+			names = append(names, z)
+		}
+	}
+	sort.Strings(names)
+
+	capacity := &infrav1beta1.Capacity{
+		LastUpdated: metav1.Now(),
+	}
+	for _, name := range names {
+		capacity.FailureDomains = append(capacity.FailureDomains, infrav1beta1.FailureDomainCapacity{
+			Name:          name,
+			ReadyMachines: counts[name],
+		})
 	}
 
-	return nil
+	maasCluster.Status.Capacity = capacity
+}
+
+// toFailureDomains converts the zones to failure domains eligible for control plane machines,
+// so KCP will distribute the CPs across multiple failure domains
+func toFailureDomains(zones []string) clusterv1.FailureDomains {
+	failureDomains := make(clusterv1.FailureDomains)
+	for _, az := range zones {
+		failureDomains[az] = clusterv1.FailureDomainSpec{
+			ControlPlane: true,
+		}
+	}
+	return failureDomains
 }
 
 // IsControlPlaneMachine checks machine is a control plane node.
@@ -217,7 +361,11 @@ func IsRunning(m *infrav1beta1.MaasMachine) bool {
 }
 
 func getExternalMachineIP(machine *infrav1beta1.MaasMachine) string {
-	for _, i := range machine.Status.Addresses {
+	return getExternalIP(machine.Status.Addresses)
+}
+
+func getExternalIP(addresses []clusterv1.MachineAddress) string {
+	for _, i := range addresses {
 		if i.Type == clusterv1.MachineExternalIP {
 			return i.Address
 		}
@@ -236,9 +384,17 @@ func (r *MaasClusterReconciler) reconcileNormal(_ context.Context, clusterScope
 		return ctrl.Result{}, nil
 	}
 
+	r.reconcileFailureDomains(clusterScope)
+	r.reconcileCapacity(clusterScope)
+
 	dnsService := dns.NewService(clusterScope)
 
-	if err := dnsService.ReconcileDNS(); err != nil {
+	if err := dnsService.ReconcileDNS(); errors.Is(err, scope.ErrReadOnly) {
+		// Leave the control plane endpoint unset, it can't be changed once set
+		conditions.MarkFalse(maasCluster, infrav1beta1.DNSReadyCondition, infrav1beta1.ReadOnlyModeReason, clusterv1.ConditionSeverityInfo,
+			"DNS resource is not created in read-only mode")
+		return reconcile.Result{}, nil
+	} else if err != nil {
 		clusterScope.Error(err, "failed to reconcile load balancer")
 		conditions.MarkFalse(maasCluster, infrav1beta1.DNSReadyCondition, infrav1beta1.DNSFailedReason, clusterv1.ConditionSeverityError, err.Error())
 		return reconcile.Result{}, err
@@ -260,10 +416,9 @@ func (r *MaasClusterReconciler) reconcileNormal(_ context.Context, clusterScope
 	// Mark the maasCluster ready
 	conditions.MarkTrue(maasCluster, infrav1beta1.DNSReadyCondition)
 
-	if err := r.reconcileDNSAttachments(clusterScope, dnsService); err != nil {
+	if pendingSince, err := r.reconcileDNSAttachments(clusterScope, dnsService); err != nil {
 		if errors.Is(err, ErrRequeueDNS) {
-			return ctrl.Result{}, nil
-			//return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+			return ctrl.Result{RequeueAfter: dnsRequeueAfter(pendingSince)}, nil
 		}
 
 		clusterScope.Error(err, "failed to reconcile load balancer")
@@ -284,13 +439,14 @@ func (r *MaasClusterReconciler) reconcileNormal(_ context.Context, clusterScope
 }
 
 // SetupWithManager will add watches for this controller
-func (r *MaasClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
+func (r *MaasClusterReconciler) SetupWithManager(_ context.Context, mgr ctrl.Manager, options controller.Options) error {
 	if r.GenericEventChannel == nil {
 		r.GenericEventChannel = make(chan event.GenericEvent)
 	}
 
 	c, err := ctrl.NewControllerManagedBy(mgr).
 		For(&infrav1beta1.MaasCluster{}).
+		WithOptions(options).
 		Watches(
 			&source.Kind{Type: &infrav1beta1.MaasMachine{}},
 			handler.EnqueueRequestsFromMapFunc(r.controlPlaneMachineToCluster),
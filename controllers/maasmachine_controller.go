@@ -27,6 +27,8 @@ import (
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/record"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/remote"
@@ -52,6 +54,45 @@ import (
 
 var ErrRequeueDNS = errors.New("need to requeue DNS")
 
+const (
+	// dnsRequeueBaseInterval is the DNS requeue interval right after the DNS attachment started waiting
+	dnsRequeueBaseInterval = 5 * time.Second
+
+	// dnsRequeueMaxInterval caps the DNS requeue interval
+	dnsRequeueMaxInterval = 2 * time.Minute
+
+	// capacityRequeueBaseInterval is the requeue interval of a machine right after it started waiting for MaaS capacity
+	capacityRequeueBaseInterval = 30 * time.Second
+
+	// capacityRequeueMaxInterval caps the requeue interval of a machine waiting for MaaS capacity
+	capacityRequeueMaxInterval = 10 * time.Minute
+
+	// redeployBootstrapTokenMinTTL is how long the join token has to stay valid after the node drain for a forced redeploy
+	redeployBootstrapTokenMinTTL = 15 * time.Minute
+)
+
+// dnsRequeueAfter returns the interval to requeue a pending DNS attachment, waiting since the given time.
+// The interval is jittered so control plane machines brought up together don't poll the DNS resource in lockstep.
+func dnsRequeueAfter(since *metav1.Time) time.Duration {
+	return backoffRequeueAfter(since, dnsRequeueBaseInterval, dnsRequeueMaxInterval)
+}
+
+// backoffRequeueAfter returns a requeue interval doubling from base as the wait since the given time goes on,
+// capped at max, jittered within [interval/2, interval]
+func backoffRequeueAfter(since *metav1.Time, base, max time.Duration) time.Duration {
+	interval := base
+	if since != nil {
+		for elapsed := time.Since(since.Time); interval < max && elapsed > 2*interval; {
+			interval *= 2
+		}
+	}
+	if interval > max {
+		interval = max
+	}
+
+	return wait.Jitter(interval/2, 1.0)
+}
+
 // MaasMachineReconciler reconciles a MaasMachine object
 type MaasMachineReconciler struct {
 	client.Client
@@ -59,10 +100,21 @@ type MaasMachineReconciler struct {
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
 	Tracker  *remote.ClusterCacheTracker
+
+	// Clientsets caches the workload cluster clientsets used to drain nodes
+	Clientsets *scope.WorkloadClientsets
+
+	// ReadOnly reconciles and reports status without making any change to MaaS
+	ReadOnly bool
+
+	// NodeDrainTimeout is the maximum time spent draining the node before the MaaS machine is released.
+	// Zero disables draining.
+	NodeDrainTimeout time.Duration
 }
 
 //+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=maasmachines,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=maasmachines/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=maasmachinetemplates,verbs=get;list;watch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;machines,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch
@@ -102,8 +154,6 @@ func (r *MaasMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, nil
 	}
 
-	log = log.WithValues("cluster", cluster.Name)
-
 	// Get Infra cluster
 	maasCluster := &infrav1beta1.MaasCluster{}
 	infraClusterName := client.ObjectKey{
@@ -112,7 +162,7 @@ func (r *MaasMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	}
 
 	if err := r.Client.Get(ctx, infraClusterName, maasCluster); err != nil {
-		log.Info("MaasCluster is not available yet")
+		log.Info("MaasCluster is not available yet", "cluster", cluster.Name)
 		return ctrl.Result{}, nil
 	}
 
@@ -124,6 +174,7 @@ func (r *MaasMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		MaasCluster:    maasCluster,
 		Tracker:        r.Tracker,
 		ControllerName: "maasmachine",
+		ReadOnly:       r.ReadOnly,
 	})
 	if err != nil {
 		return ctrl.Result{}, err
@@ -131,13 +182,15 @@ func (r *MaasMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 	// Create the machine scope
 	machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
-		Logger:       log,
-		Client:       r.Client,
-		Tracker:      r.Tracker,
-		Cluster:      cluster,
-		ClusterScope: clusterScope,
-		Machine:      machine,
-		MaasMachine:  maasMachine,
+		Logger:         log,
+		Client:         r.Client,
+		Tracker:        r.Tracker,
+		Clientsets:     r.Clientsets,
+		Cluster:        cluster,
+		ClusterScope:   clusterScope,
+		Machine:        machine,
+		MaasMachine:    maasMachine,
+		ControllerName: "maasmachine",
 	})
 	if err != nil {
 		log.Error(err, "failed to create scope")
@@ -181,23 +234,52 @@ func (r *MaasMachineReconciler) reconcileDelete(_ context.Context, machineScope
 		return ctrl.Result{}, nil
 	}
 
-	if err := r.reconcileDNSAttachment(machineScope, clusterScope, m); err != nil {
-		if errors.Is(err, ErrRequeueDNS) {
-			return ctrl.Result{}, nil
-			//return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
-		}
+	// The core Machine controller drains the node before deleting its MaasMachine; only drain when it didn't,
+	// e.g. the MaasMachine is deleted on its own
+	if !conditions.IsTrue(machineScope.Machine, clusterv1.DrainingSucceededCondition) && !r.drainNode(machineScope, maasMachine.DeletionTimestamp.Time) {
+		return ctrl.Result{RequeueAfter: 20 * time.Second}, nil
+	}
 
-		machineScope.Error(err, "failed to reconcile LB attachment")
-		return ctrl.Result{}, err
+	// The cluster doesn't detach the IP in read-only mode, so don't wait for it
+	if !clusterScope.ReadOnly() {
+		if err := r.reconcileDNSAttachment(machineScope, clusterScope, m); err != nil {
+			if errors.Is(err, ErrRequeueDNS) {
+				return ctrl.Result{}, nil
+				//return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+			}
+
+			machineScope.Error(err, "failed to reconcile LB attachment")
+			return ctrl.Result{}, err
+		}
 	}
 
-	if err := machineSvc.ReleaseMachine(m.ID); err != nil {
-		machineScope.Error(err, "failed to release machine")
-		return ctrl.Result{}, err
+	if maasMachine.Spec.ReleasePolicy == infrav1beta1.ReleasePolicyRetain {
+		// The retained machine keeps running its kubelet, which registers a new Node once the Machine
+		// controller deleted this one. Cordon it meanwhile; the new Node is schedulable again.
+		if machineScope.Machine.Status.NodeRef != nil {
+			if err := machineScope.CordonNode(); err != nil {
+				machineScope.Error(err, "failed to cordon node of retained machine")
+				return ctrl.Result{}, err
+			}
+		}
+		machineScope.Info("Retaining machine per release policy")
+		r.Recorder.Eventf(machineScope.MaasMachine, corev1.EventTypeNormal, "SuccessfulRetain",
+			"Retained instance %q; its kubelet keeps running and registers its Node again until it's released or redeployed in MaaS", m.ID)
+	} else {
+		err := machineSvc.ReleaseMachine(m.ID, fmt.Sprintf("capmaas: delete %s/%s", machineScope.Cluster.Name, maasMachine.Name))
+		if err != nil && !errors.Is(err, scope.ErrReadOnly) {
+			machineScope.Error(err, "failed to release machine")
+			return ctrl.Result{}, err
+		}
+
+		if err == nil {
+			r.Recorder.Eventf(machineScope.MaasMachine, corev1.EventTypeNormal, "SuccessfulRelease", "Released instance %q", m.ID)
+		} else {
+			r.Recorder.Eventf(machineScope.MaasMachine, corev1.EventTypeWarning, "MachineLeftBehind", "Read-only mode; instance %q is left allocated in MaaS", m.ID)
+		}
 	}
 
 	conditions.MarkFalse(machineScope.MaasMachine, infrav1beta1.MachineDeployedCondition, clusterv1.DeletedReason, clusterv1.ConditionSeverityInfo, "")
-	r.Recorder.Eventf(machineScope.MaasMachine, corev1.EventTypeNormal, "SuccessfulRelease", "Released instance %q", m.ID)
 
 	// Machine is deleted so remove the finalizer.
 	controllerutil.RemoveFinalizer(maasMachine, infrav1beta1.MachineFinalizer)
@@ -209,6 +291,37 @@ func (r *MaasMachineReconciler) reconcileDelete(_ context.Context, machineScope
 	return reconcile.Result{}, nil
 }
 
+// drainNode drains the node of the machine before its MaaS machine is released, giving up NodeDrainTimeout after start.
+// It returns true once the MaaS machine can be released.
+func (r *MaasMachineReconciler) drainNode(machineScope *scope.MachineScope, start time.Time) bool {
+	if r.NodeDrainTimeout <= 0 || machineScope.Machine.Status.NodeRef == nil {
+		return true
+	}
+
+	maasMachine := machineScope.MaasMachine
+	nodeName := machineScope.Machine.Status.NodeRef.Name
+
+	if time.Since(start) > r.NodeDrainTimeout {
+		machineScope.Info("Node drain timed out; releasing anyway", "timeout", r.NodeDrainTimeout)
+		r.Recorder.Eventf(maasMachine, corev1.EventTypeWarning, "DrainNodeTimeout", "Drain of node %q timed out after %s", nodeName, r.NodeDrainTimeout)
+		return true
+	}
+
+	drained, err := machineScope.DrainNode()
+	if err != nil {
+		machineScope.Error(err, "Unable to drain node")
+		r.Recorder.Eventf(maasMachine, corev1.EventTypeWarning, "FailedDrainNode", "Unable to drain node %q: %v", nodeName, err)
+		return false
+	}
+	if !drained {
+		machineScope.Info("Node is being drained; requeue")
+		return false
+	}
+
+	r.Recorder.Eventf(maasMachine, corev1.EventTypeNormal, "SuccessfulDrainNode", "Drained node %q", nodeName)
+	return true
+}
+
 // findInstance queries the EC2 apis and retrieves the instance if it exists, returns nil otherwise.
 func (r *MaasMachineReconciler) findMachine(machineScope *scope.MachineScope, machineSvc *maasmachine.Service) (*infrav1beta1.Machine, error) {
 	id := machineScope.GetInstanceID()
@@ -258,12 +371,111 @@ func (r *MaasMachineReconciler) reconcileNormal(_ context.Context, machineScope
 		return ctrl.Result{}, err
 	}
 
+	// Release a deployed machine marked for force redeploy; it's then redeployed through the regular deploy path
+	if _, ok := maasMachine.Annotations[infrav1beta1.ForceRedeployAnnotation]; ok && m != nil {
+		switch m.State {
+		case infrav1beta1.MachineStateDeployed:
+			// Releasing a control plane machine in place would leave its etcd member and API server DNS record behind
+			if IsControlPlaneMachine(maasMachine) {
+				machineScope.Info("Control plane machine can't be force redeployed; ignoring")
+				r.Recorder.Eventf(maasMachine, corev1.EventTypeWarning, "ForceRedeploySkipped", "Control plane instance %q can't be redeployed in place; replace its Machine instead", m.ID)
+				delete(maasMachine.Annotations, infrav1beta1.ForceRedeployAnnotation)
+				break
+			}
+
+			// Keep the annotation, the redeploy starts once the read-only mode is turned off
+			if machineScope.ReadOnly() {
+				machineScope.Info("Read-only mode; skipping force redeploy")
+				r.Recorder.Eventf(maasMachine, corev1.EventTypeNormal, "ForceRedeploySkipped", "Read-only mode; instance %q isn't redeployed", m.ID)
+				break
+			}
+
+			// Reset the condition so the drain timeout starts with the redeploy
+			if conditions.GetReason(maasMachine, infrav1beta1.MachineDeployedCondition) != infrav1beta1.MachineRedeployingReason {
+				// The machine is redeployed with its current bootstrap data, check its join token lasts through the redeploy
+				usable, err := machineScope.BootstrapTokenUsable(r.NodeDrainTimeout + redeployBootstrapTokenMinTTL)
+				if err != nil {
+					machineScope.Error(err, "unable to check bootstrap token")
+					return ctrl.Result{}, err
+				}
+				if !usable {
+					machineScope.Info("Bootstrap token is expired; ignoring force redeploy")
+					r.Recorder.Eventf(maasMachine, corev1.EventTypeWarning, "ForceRedeploySkipped", "Bootstrap token of instance %q is expired; replace its Machine instead", m.ID)
+					delete(maasMachine.Annotations, infrav1beta1.ForceRedeployAnnotation)
+					break
+				}
+
+				conditions.Delete(maasMachine, infrav1beta1.MachineDeployedCondition)
+				conditions.MarkFalse(maasMachine, infrav1beta1.MachineDeployedCondition, infrav1beta1.MachineRedeployingReason, clusterv1.ConditionSeverityInfo, "")
+			}
+			if !r.drainNode(machineScope, conditions.GetLastTransitionTime(maasMachine, infrav1beta1.MachineDeployedCondition).Time) {
+				return ctrl.Result{RequeueAfter: 20 * time.Second}, nil
+			}
+
+			machineScope.Info("Releasing machine to force redeploy")
+			reason := fmt.Sprintf("capmaas: force redeploy %s/%s", machineScope.Cluster.Name, maasMachine.Name)
+			if err := machineSvc.ReleaseMachine(m.ID, reason); errors.Is(err, scope.ErrReadOnly) {
+				return ctrl.Result{}, nil
+			} else if err != nil {
+				machineScope.Error(err, "failed to release machine for redeploy")
+				return ctrl.Result{}, err
+			}
+			machineScope.SetNotReady()
+			machineScope.SetMachineState(infrav1beta1.MachineStateReleasing)
+			machineScope.SetPowered(false)
+			r.Recorder.Eventf(maasMachine, corev1.EventTypeNormal, "ForceRedeployRelease", "Released instance %q to redeploy it", m.ID)
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		case infrav1beta1.MachineStateReleasing, infrav1beta1.MachineStateDiskErasing:
+			machineScope.Info("Waiting for machine to be released to force redeploy")
+			machineScope.SetNotReady()
+			machineScope.SetMachineState(m.State)
+			machineScope.SetPowered(m.Powered)
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		case infrav1beta1.MachineStateReady, infrav1beta1.MachineStateAllocated:
+			// Redeployed below. From its release until DeployMachine allocates it again by system ID, the machine
+			// is back in the MaaS Ready pool and another MaaS user can allocate it first. This controller can't
+			// tell the new owner apart, so the annotation should only be used on machines reserved to the cluster
+			// (e.g. through a dedicated resource pool).
+		default:
+			machineScope.Info("Machine state isn't safe to force redeploy; ignoring")
+			r.Recorder.Eventf(maasMachine, corev1.EventTypeWarning, "ForceRedeploySkipped", "Instance %q in state %q can't be redeployed", m.ID, m.State)
+			delete(maasMachine.Annotations, infrav1beta1.ForceRedeployAnnotation)
+		}
+	}
+
 	// Create new m
 	// TODO(saamalik) confirm that we'll never "recreate" a m; e.g: findMachine should always return err
 	// if there used to be a m
 	if m == nil || !(m.State == infrav1beta1.MachineStateDeployed || m.State == infrav1beta1.MachineStateDeploying) {
-		// Avoid a flickering condition between Started and Failed if there's a persistent failure with createInstance
-		if conditions.GetReason(machineScope.MaasMachine, infrav1beta1.MachineDeployedCondition) != infrav1beta1.MachineDeployFailedReason {
+		if m == nil && machineScope.GetProviderID() == "" {
+			defaults, err := machineScope.GetMachineTemplateDefaults()
+			if err != nil {
+				machineScope.Error(err, "unable to get machine template defaults")
+				return ctrl.Result{}, err
+			}
+			machineScope.ApplyTemplateDefaults(defaults)
+
+			// Wait quietly for capacity instead of failing the allocation over and over
+			available, err := machineSvc.ReadyMachineCount()
+			if err != nil {
+				machineScope.Error(err, "unable to check MaaS capacity")
+				return ctrl.Result{}, err
+			}
+			if available == 0 {
+				machineScope.Info("No MaaS machine available for allocation; waiting for capacity")
+				conditions.MarkFalse(machineScope.MaasMachine, infrav1beta1.MachineDeployedCondition, infrav1beta1.MachineNoCapacityReason, clusterv1.ConditionSeverityWarning,
+					"no MaaS machine is available for allocation")
+				since := conditions.GetLastTransitionTime(machineScope.MaasMachine, infrav1beta1.MachineDeployedCondition)
+				return ctrl.Result{RequeueAfter: backoffRequeueAfter(since, capacityRequeueBaseInterval, capacityRequeueMaxInterval)}, nil
+			}
+		}
+
+		// Avoid a flickering condition between Started and Failed if there's a persistent failure with createInstance,
+		// or between Started and NoCapacity which would reset the capacity backoff.
+		// A forced redeploy keeps its reason until it's deployed, DeployMachine only allocates the released machine again then
+		deployedReason := conditions.GetReason(machineScope.MaasMachine, infrav1beta1.MachineDeployedCondition)
+		redeploying := deployedReason == infrav1beta1.MachineRedeployingReason
+		if !redeploying && deployedReason != infrav1beta1.MachineDeployFailedReason && deployedReason != infrav1beta1.MachineNoCapacityReason {
 			conditions.MarkFalse(machineScope.MaasMachine, infrav1beta1.MachineDeployedCondition, infrav1beta1.MachineDeployStartedReason, clusterv1.ConditionSeverityInfo, "")
 			if patchErr := machineScope.PatchObject(); patchErr != nil {
 				machineScope.Error(patchErr, "failed to patch conditions")
@@ -271,21 +483,50 @@ func (r *MaasMachineReconciler) reconcileNormal(_ context.Context, machineScope
 			}
 		}
 		m, err = r.deployMachine(machineScope, machineSvc)
+		if errors.Is(err, scope.ErrReadOnly) && redeploying {
+			conditions.MarkFalse(machineScope.MaasMachine, infrav1beta1.MachineDeployedCondition, infrav1beta1.MachineRedeployingReason, clusterv1.ConditionSeverityInfo, "read-only mode")
+			return ctrl.Result{}, nil
+		}
+		if errors.Is(err, scope.ErrReadOnly) {
+			conditions.MarkFalse(machineScope.MaasMachine, infrav1beta1.MachineDeployedCondition, infrav1beta1.ReadOnlyModeReason, clusterv1.ConditionSeverityInfo, "")
+			return ctrl.Result{}, nil
+		}
+		if err != nil && redeploying {
+			machineScope.Error(err, "unable to redeploy m")
+			conditions.MarkFalse(machineScope.MaasMachine, infrav1beta1.MachineDeployedCondition, infrav1beta1.MachineRedeployingReason, clusterv1.ConditionSeverityError, err.Error())
+			return ctrl.Result{}, err
+		}
+		if errors.Is(err, maasmachine.ErrNoCapacity) {
+			// The Ready count doesn't apply every allocation constraint, so wait for capacity here as well
+			machineScope.Info("No MaaS machine matches the allocation constraints; waiting for capacity")
+			conditions.MarkFalse(machineScope.MaasMachine, infrav1beta1.MachineDeployedCondition, infrav1beta1.MachineNoCapacityReason, clusterv1.ConditionSeverityWarning,
+				"no MaaS machine matches the allocation constraints")
+			since := conditions.GetLastTransitionTime(machineScope.MaasMachine, infrav1beta1.MachineDeployedCondition)
+			return ctrl.Result{RequeueAfter: backoffRequeueAfter(since, capacityRequeueBaseInterval, capacityRequeueMaxInterval)}, nil
+		}
 		if err != nil {
 			machineScope.Error(err, "unable to create m")
 			conditions.MarkFalse(machineScope.MaasMachine, infrav1beta1.MachineDeployedCondition, infrav1beta1.MachineDeployFailedReason, clusterv1.ConditionSeverityError, err.Error())
 			return ctrl.Result{}, err
 		}
+
+		if _, ok := maasMachine.Annotations[infrav1beta1.ForceRedeployAnnotation]; ok {
+			r.Recorder.Eventf(maasMachine, corev1.EventTypeNormal, "ForceRedeploy", "Redeploying instance %q", m.ID)
+			delete(maasMachine.Annotations, infrav1beta1.ForceRedeployAnnotation)
+		}
 	}
 
 	// Make sure Spec.ProviderID and Spec.InstanceID are always set.
+	if machineScope.MaasMachine.Spec.SystemID == nil {
+		machineScope.Logger = machineScope.Logger.WithValues("system-id", m.ID)
+	}
 	machineScope.SetProviderID(m.ID, m.AvailabilityZone)
 	machineScope.SetFailureDomain(m.AvailabilityZone)
 	machineScope.SetSystemID(m.ID)
 	machineScope.SetMachineHostname(m.Hostname)
 
 	existingMachineState := machineScope.GetMachineState()
-	machineScope.Logger = machineScope.Logger.WithValues("state", m.State, "m-id", *machineScope.GetInstanceID())
+	machineScope.Logger = machineScope.Logger.WithValues("state", m.State)
 	machineScope.SetMachineState(m.State)
 	machineScope.SetPowered(m.Powered)
 
@@ -304,12 +545,18 @@ func (r *MaasMachineReconciler) reconcileNormal(_ context.Context, machineScope
 		machineScope.SetFailureMessage(errors.Errorf("Maas machine state %q is unexpected", m.State))
 	case machineScope.MachineIsInKnownState() && !m.Powered:
 		if *machineScope.GetMachineState() == infrav1beta1.MachineStateDeployed {
-			machineScope.Info("Deployed machine is powered off trying power on")
-			if err := machineSvc.PowerOnMachine(); err != nil {
-				return ctrl.Result{}, errors.Wrap(err, "unable to power on deployed machine")
+			if machineScope.ManagePower() {
+				machineScope.Info("Deployed machine is powered off trying power on")
+				err := machineSvc.PowerOnMachine()
+				if err == nil {
+					return ctrl.Result{RequeueAfter: 1 * time.Minute}, nil
+				}
+				if !errors.Is(err, scope.ErrReadOnly) {
+					return ctrl.Result{}, errors.Wrap(err, "unable to power on deployed machine")
+				}
+			} else {
+				machineScope.Info("Deployed machine is powered off; power is not managed")
 			}
-
-			return ctrl.Result{RequeueAfter: 1 * time.Minute}, nil
 		}
 
 		machineScope.SetNotReady()
@@ -339,7 +586,8 @@ func (r *MaasMachineReconciler) reconcileNormal(_ context.Context, machineScope
 
 		if err := r.reconcileDNSAttachment(machineScope, clusterScope, m); err != nil {
 			if errors.Is(err, ErrRequeueDNS) {
-				return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+				since := conditions.GetLastTransitionTime(machineScope.MaasMachine, infrav1beta1.DNSAttachedCondition)
+				return ctrl.Result{RequeueAfter: dnsRequeueAfter(since)}, nil
 			}
 			machineScope.Error(err, "failed to reconcile DNS attachment")
 			return ctrl.Result{}, err
@@ -356,11 +604,27 @@ func (r *MaasMachineReconciler) reconcileNormal(_ context.Context, machineScope
 		machineScope.Info("Machine is not operational; requeue")
 		return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
 	} else {
+		registered, err := machineScope.IsNodeRegistered()
+		if err != nil {
+			machineScope.Error(err, "Unable to check node registration")
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		} else if !registered {
+			machineScope.Info("Node is not registered yet; requeue")
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+
 		if err := machineScope.SetNodeProviderID(); err != nil {
 			machineScope.Error(err, "Unable to set Node hostname")
 			r.Recorder.Eventf(machineScope.MaasMachine, corev1.EventTypeWarning, "NodeProviderUpdateFailed", "Unable to set the node provider update")
 			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
 		}
+
+		// Also without NodeLabelPrefixes, to remove the labels set before they were changed
+		if err := machineScope.SetNodeLabels(); err != nil {
+			machineScope.Error(err, "Unable to set Node labels")
+			r.Recorder.Eventf(machineScope.MaasMachine, corev1.EventTypeWarning, "NodeLabelsUpdateFailed", "Unable to set the node labels")
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		}
 	}
 
 	return ctrl.Result{}, nil
@@ -385,7 +649,16 @@ func (r *MaasMachineReconciler) deployMachine(machineScope *scope.MachineScope,
 func (r *MaasMachineReconciler) resolveUserData(machineScope *scope.MachineScope) (string, error) {
 	userData, err := machineScope.GetRawBootstrapData()
 	if err != nil {
-		r.Recorder.Eventf(machineScope.MaasMachine, corev1.EventTypeWarning, "FailedGetBootstrapData", err.Error())
+		reason := "FailedGetBootstrapData"
+		switch {
+		case errors.Is(err, scope.ErrBootstrapDataSecretNotFound):
+			reason = "BootstrapDataSecretNotFound"
+		case errors.Is(err, scope.ErrBootstrapDataKeyMissing):
+			reason = "BootstrapDataKeyMissing"
+		case errors.Is(err, scope.ErrBootstrapDataEmpty):
+			reason = "BootstrapDataEmpty"
+		}
+		r.Recorder.Eventf(machineScope.MaasMachine, corev1.EventTypeWarning, reason, err.Error())
 		return "", err
 	}
 
@@ -414,7 +687,8 @@ func (r *MaasMachineReconciler) reconcileDNSAttachment(machineScope *scope.Machi
 
 		if registered {
 			// Wait for Cluster to delete this guy
-			conditions.MarkFalse(machineScope.MaasMachine, infrav1beta1.DNSAttachedCondition, infrav1beta1.DNSDetachPending, clusterv1.ConditionSeverityWarning, "")
+			conditions.MarkFalse(machineScope.MaasMachine, infrav1beta1.DNSAttachedCondition, infrav1beta1.DNSDetachPending, clusterv1.ConditionSeverityWarning,
+				"waiting for IP %q to be removed from DNS resource %q", getExternalIP(m.Addresses), clusterScope.GetDNSName())
 			machineScope.Info("machine waiting for cluster to de-register DNS")
 			return ErrRequeueDNS
 		}
@@ -433,7 +707,8 @@ func (r *MaasMachineReconciler) reconcileDNSAttachment(machineScope *scope.Machi
 	machineScope.MaasMachine.Status.DNSAttached = registered
 
 	if !registered {
-		conditions.MarkFalse(machineScope.MaasMachine, infrav1beta1.DNSAttachedCondition, infrav1beta1.DNSAttachPending, clusterv1.ConditionSeverityWarning, "")
+		conditions.MarkFalse(machineScope.MaasMachine, infrav1beta1.DNSAttachedCondition, infrav1beta1.DNSAttachPending, clusterv1.ConditionSeverityWarning,
+			"waiting for IP %q to be added to DNS resource %q", getExternalIP(m.Addresses), clusterScope.GetDNSName())
 		// Wait for Cluster to add me
 		machineScope.Info("machine waiting for cluster to register DNS")
 		return ErrRequeueDNS
@@ -447,6 +722,10 @@ func (r *MaasMachineReconciler) reconcileDNSAttachment(machineScope *scope.Machi
 
 // SetupWithManager will add watches for this controller
 func (r *MaasMachineReconciler) SetupWithManager(_ context.Context, mgr ctrl.Manager, options controller.Options) error {
+	if r.Clientsets == nil {
+		r.Clientsets = scope.NewWorkloadClientsets()
+	}
+
 	clusterToMaasMachines, err := util.ClusterToObjectsMapper(mgr.GetClient(), &infrav1beta1.MaasMachineList{}, mgr.GetScheme())
 	if err != nil {
 		return err
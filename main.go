@@ -19,9 +19,11 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"math/rand"
 	"os"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"strings"
 	"time"
 
 	"sigs.k8s.io/cluster-api/controllers/remote"
@@ -30,12 +32,14 @@ import (
 
 	"github.com/spf13/pflag"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/klog/v2"
 	"k8s.io/klog/v2/klogr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/feature"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 
 	infrav1alpha3 "github.com/spectrocloud/cluster-api-provider-maas/api/v1alpha3"
@@ -51,8 +55,16 @@ var (
 	//flags
 	metricsBindAddr      string
 	enableLeaderElection bool
+	leaderElectionNS     string
+	leaseDuration        time.Duration
+	renewDeadline        time.Duration
+	retryPeriod          time.Duration
 	syncPeriod           time.Duration
 	machineConcurrency   int
+	clusterConcurrency   int
+	nodeDrainTimeout     time.Duration
+	apiServerHCTimeout   time.Duration
+	readOnly             bool
 	healthAddr           string
 	webhookPort          int
 	watchNamespace       string
@@ -76,22 +88,42 @@ func main() {
 	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
 	pflag.Parse()
 
-	if watchNamespace != "" {
-		setupLog.Info("Watching cluster-api objects only in namespace for reconciliation", "namespace", watchNamespace)
+	watchNamespaces, err := parseWatchNamespaces(watchNamespace)
+	if err != nil {
+		setupLog.Error(err, "invalid namespace flag")
+		os.Exit(1)
+	}
+	if len(watchNamespaces) > 0 {
+		setupLog.Info("Watching cluster-api objects only in namespaces for reconciliation", "namespaces", watchNamespaces)
 	}
 
 	ctrl.SetLogger(klogr.New())
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
-		Scheme:                 scheme,
-		MetricsBindAddress:     metricsBindAddr,
-		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "controller-leader-election-capmaas",
-		SyncPeriod:             &syncPeriod,
-		HealthProbeBindAddress: healthAddr,
-		Port:                   webhookPort,
-		Namespace:              watchNamespace,
-	})
+	if readOnly {
+		setupLog.Info("Running in read-only mode; no change will be made to MaaS")
+	}
+
+	options := ctrl.Options{
+		Scheme:                  scheme,
+		MetricsBindAddress:      metricsBindAddr,
+		LeaderElection:          enableLeaderElection,
+		LeaderElectionID:        "controller-leader-election-capmaas",
+		LeaderElectionNamespace: leaderElectionNS,
+		LeaseDuration:           &leaseDuration,
+		RenewDeadline:           &renewDeadline,
+		RetryPeriod:             &retryPeriod,
+		SyncPeriod:              &syncPeriod,
+		HealthProbeBindAddress:  healthAddr,
+		Port:                    webhookPort,
+	}
+	switch {
+	case len(watchNamespaces) == 1:
+		options.Namespace = watchNamespaces[0]
+	case len(watchNamespaces) > 1:
+		options.NewCache = cache.MultiNamespacedCacheBuilder(watchNamespaces)
+	}
+
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), options)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
@@ -136,21 +168,25 @@ func main() {
 	}
 
 	if err := (&controllers.MaasMachineReconciler{
-		Client:   mgr.GetClient(),
-		Log:      ctrl.Log.WithName("controllers").WithName("MaasMachine"),
-		Recorder: mgr.GetEventRecorderFor("maasmachine-controller"),
-		Tracker:  tracker,
+		Client:           mgr.GetClient(),
+		Log:              ctrl.Log.WithName("controllers").WithName("MaasMachine"),
+		Recorder:         mgr.GetEventRecorderFor("maasmachine-controller"),
+		Tracker:          tracker,
+		ReadOnly:         readOnly,
+		NodeDrainTimeout: nodeDrainTimeout,
 	}).SetupWithManager(ctx, mgr, concurrency(machineConcurrency)); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "MaasMachine")
 		os.Exit(1)
 	}
 
 	if err := (&controllers.MaasClusterReconciler{
-		Client:   mgr.GetClient(),
-		Log:      ctrl.Log.WithName("controllers").WithName("MaasCluster"),
-		Recorder: mgr.GetEventRecorderFor("maascluster-controller"),
-		Tracker:  tracker,
-	}).SetupWithManager(mgr); err != nil {
+		Client:                      mgr.GetClient(),
+		Log:                         ctrl.Log.WithName("controllers").WithName("MaasCluster"),
+		Recorder:                    mgr.GetEventRecorderFor("maascluster-controller"),
+		Tracker:                     tracker,
+		ReadOnly:                    readOnly,
+		APIServerHealthCheckTimeout: apiServerHCTimeout,
+	}).SetupWithManager(ctx, mgr, concurrency(clusterConcurrency)); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "MaasCluster")
 		os.Exit(1)
 	}
@@ -183,8 +219,24 @@ func initFlags(fs *pflag.FlagSet) {
 		"The address the metric endpoint binds to.")
 	fs.IntVar(&machineConcurrency, "machine-concurrency", 2,
 		"The number of maas machines to process simultaneously")
+	fs.IntVar(&clusterConcurrency, "cluster-concurrency", 1,
+		"The number of maas clusters to process simultaneously")
+	fs.DurationVar(&nodeDrainTimeout, "node-drain-timeout", 0,
+		"The maximum time to drain a node before its maas machine is released (e.g. 5m). Draining is disabled when 0")
+	fs.DurationVar(&apiServerHCTimeout, "apiserver-health-check-timeout", 0,
+		"The timeout of the API server port probe done before a control plane machine is added to the cluster DNS (e.g. 5s). The probe is disabled when 0")
+	fs.BoolVar(&readOnly, "read-only", false,
+		"Reconcile and report status without making any change to MaaS (no allocate, deploy, release, power on or DNS changes)")
 	fs.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. Enabling this will ensure there is only one active controller manager.")
+	fs.StringVar(&leaderElectionNS, "leader-elect-namespace", "",
+		"Namespace of the leader election lease. If unspecified, the namespace the controller runs in is used.")
+	fs.DurationVar(&leaseDuration, "leader-elect-lease-duration", 15*time.Second,
+		"Interval at which non-leader candidates will wait to force acquire leadership (duration string)")
+	fs.DurationVar(&renewDeadline, "leader-elect-renew-deadline", 10*time.Second,
+		"Duration that the leading controller manager will retry refreshing leadership before giving up (duration string)")
+	fs.DurationVar(&retryPeriod, "leader-elect-retry-period", 2*time.Second,
+		"Duration the LeaderElector clients should wait between tries of actions (duration string)")
 	fs.DurationVar(&syncPeriod, "sync-period", 120*time.Minute,
 		"The minimum interval at which watched resources are reconciled (e.g. 15m)")
 	fs.StringVar(&healthAddr, "health-addr", ":9440",
@@ -192,12 +244,30 @@ func initFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&webhookPort, "webhook-port", 9443,
 		"Webhook Server port")
 	fs.StringVar(&watchNamespace, "namespace", "",
-		"Namespace that the controller watches to reconcile cluster-api objects. If unspecified, the controller watches for cluster-api objects across all namespaces.",
+		"Comma separated list of namespaces that the controller watches to reconcile cluster-api objects. If unspecified, the controller watches for cluster-api objects across all namespaces.",
 	)
 
 	feature.MutableGates.AddFlag(fs)
 }
 
+// parseWatchNamespaces splits the comma separated namespace flag, validating each namespace
+func parseWatchNamespaces(namespaces string) ([]string, error) {
+	if namespaces == "" {
+		return nil, nil
+	}
+
+	var result []string
+	for _, ns := range strings.Split(namespaces, ",") {
+		ns = strings.TrimSpace(ns)
+		if errs := validation.IsDNS1123Label(ns); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid namespace %q: %s", ns, strings.Join(errs, ", "))
+		}
+		result = append(result, ns)
+	}
+
+	return result, nil
+}
+
 func concurrency(c int) controller.Options {
 	return controller.Options{MaxConcurrentReconciles: c}
 }
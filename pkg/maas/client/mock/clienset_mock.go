@@ -14,7 +14,7 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/spectrocloud/maas-client-go/maasclient (interfaces: ClientSetInterface,Machines,Machine,DNSResources,DNSResource,DNSResourceBuilder,DNSResourceModifier,IPAddress,Zone,MachineReleaser,MachineAllocator,MachineModifier,MachineDeployer)
+// Source: github.com/spectrocloud/maas-client-go/maasclient (interfaces: ClientSetInterface,Machines,Machine,DNSResources,DNSResource,DNSResourceBuilder,DNSResourceModifier,IPAddress,Zone,Zones,MachineReleaser,MachineAllocator,MachineModifier,MachineDeployer)
 
 // Package mock_clientset is a generated GoMock package.
 package mock_clientset
@@ -1011,6 +1011,44 @@ func (mr *MockZoneMockRecorder) Name() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockZone)(nil).Name))
 }
 
+// MockZones is a mock of Zones interface.
+type MockZones struct {
+	ctrl     *gomock.Controller
+	recorder *MockZonesMockRecorder
+}
+
+// MockZonesMockRecorder is the mock recorder for MockZones.
+type MockZonesMockRecorder struct {
+	mock *MockZones
+}
+
+// NewMockZones creates a new mock instance.
+func NewMockZones(ctrl *gomock.Controller) *MockZones {
+	mock := &MockZones{ctrl: ctrl}
+	mock.recorder = &MockZonesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockZones) EXPECT() *MockZonesMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockZones) List(arg0 context.Context) ([]maasclient.Zone, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0)
+	ret0, _ := ret[0].([]maasclient.Zone)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockZonesMockRecorder) List(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockZones)(nil).List), arg0)
+}
+
 // MockMachineReleaser is a mock of MachineReleaser interface.
 type MockMachineReleaser struct {
 	ctrl     *gomock.Controller
@@ -7,6 +7,9 @@ import (
 	"github.com/spectrocloud/cluster-api-provider-maas/pkg/maas/scope"
 	"github.com/spectrocloud/maas-client-go/maasclient"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"net"
+	"strconv"
+	"time"
 )
 
 type Service struct {
@@ -29,6 +32,12 @@ func (s *Service) ReconcileDNS() error {
 	s.scope.V(2).Info("Reconciling DNS")
 	ctx := context.TODO()
 
+	// Don't generate a DNS name which would never be created in MaaS
+	if s.scope.ReadOnly() && s.scope.Cluster.Spec.ControlPlaneEndpoint.IsZero() && s.scope.MaasCluster.Status.Network.DNSName == "" {
+		s.scope.Info("Read-only mode; skipping DNS resource creation")
+		return scope.ErrReadOnly
+	}
+
 	dnsResource, err := s.GetDNSResource()
 	if err != nil && !errors.Is(err, ErrNotFound) {
 		return err
@@ -36,7 +45,10 @@ func (s *Service) ReconcileDNS() error {
 
 	dnsName := s.scope.GetDNSName()
 
-	if dnsResource == nil {
+	if dnsResource == nil && s.scope.ReadOnly() {
+		s.scope.Info("Read-only mode; skipping DNS resource creation", "dns-name", dnsName)
+		return scope.ErrReadOnly
+	} else if dnsResource == nil {
 		if _, err = s.maasClient.DNSResources().
 			Builder().
 			WithFQDN(s.scope.GetDNSName()).
@@ -54,6 +66,11 @@ func (s *Service) ReconcileDNS() error {
 
 // UpdateAttachments reconciles the load balancers for the given cluster.
 func (s *Service) UpdateDNSAttachments(IPs []string) error {
+	if s.scope.ReadOnly() {
+		s.scope.Info("Read-only mode; skipping DNS attachments update", "ips", IPs)
+		return scope.ErrReadOnly
+	}
+
 	s.scope.V(2).Info("Updating DNS Attachments")
 	ctx := context.TODO()
 	// get ID of loadbalancer
@@ -66,9 +83,53 @@ func (s *Service) UpdateDNSAttachments(IPs []string) error {
 		return errors.Wrap(err, "Unable to update IPs")
 	}
 
+	s.scope.SetDNSAttachedIPs(sets.NewString(IPs...).List())
+
 	return nil
 }
 
+// DeleteDNS deletes the API server DNS resource of the cluster, if it was ever created
+func (s *Service) DeleteDNS() error {
+	if s.scope.MaasCluster.Status.Network.DNSName == "" {
+		return nil
+	}
+
+	if s.scope.ReadOnly() {
+		s.scope.Info("Read-only mode; skipping DNS resource deletion")
+		return scope.ErrReadOnly
+	}
+
+	s.scope.V(2).Info("Deleting DNS")
+	ctx := context.TODO()
+
+	dnsResource, err := s.GetDNSResource()
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if err := dnsResource.Delete(ctx); err != nil {
+		return errors.Wrapf(err, "Unable to delete DNS Resource %q", dnsResource.FQDN())
+	}
+
+	return nil
+}
+
+// APIServerIsResponding returns true if the API server port of the machine IP accepts connections within the timeout
+func (s *Service) APIServerIsResponding(ip string, timeout time.Duration) bool {
+	address := net.JoinHostPort(ip, strconv.Itoa(s.scope.APIServerPort()))
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		s.scope.V(2).Info("API server not responding", "address", address, "reason", err.Error())
+		return false
+	}
+	_ = conn.Close()
+
+	return true
+}
+
 // TODO do at some point
 //func MachineIsRunning(m *infrainfrav1beta1.MaasMachine) bool {
 //	if !m.Status.MachinePowered {
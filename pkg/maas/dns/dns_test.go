@@ -4,12 +4,16 @@ import (
 	"context"
 	"net"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/klog/v2/klogr"
 	"sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	infrav1beta1 "github.com/spectrocloud/cluster-api-provider-maas/api/v1beta1"
 	mockclientset "github.com/spectrocloud/cluster-api-provider-maas/pkg/maas/client/mock"
@@ -124,3 +128,178 @@ func TestDNS(t *testing.T) {
 		g.Expect(res).To(BeTrue())
 	})
 }
+
+func TestDeleteDNS(t *testing.T) {
+	log := klogr.New()
+	cluster := &v1beta1.Cluster{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "a",
+		},
+	}
+
+	t.Run("delete dns resource", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		mockClientSetInterface := mockclientset.NewMockClientSetInterface(ctrl)
+		mockDNSResources := mockclientset.NewMockDNSResources(ctrl)
+		mockDNSResource := mockclientset.NewMockDNSResource(ctrl)
+		s := &Service{
+			scope: &scope.ClusterScope{
+				Logger:  log,
+				Cluster: cluster,
+				MaasCluster: &infrav1beta1.MaasCluster{
+					Status: infrav1beta1.MaasClusterStatus{
+						Network: infrav1beta1.Network{DNSName: "a-abcde.b.com"},
+					},
+				},
+			},
+			maasClient: mockClientSetInterface,
+		}
+		mockClientSetInterface.EXPECT().DNSResources().Return(mockDNSResources)
+		mockDNSResources.EXPECT().List(context.Background(), gomock.Any()).Return([]maasclient.DNSResource{mockDNSResource}, nil)
+		mockDNSResource.EXPECT().Delete(context.TODO()).Return(nil)
+
+		g.Expect(s.DeleteDNS()).To(Succeed())
+	})
+
+	t.Run("dns resource already deleted", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		mockClientSetInterface := mockclientset.NewMockClientSetInterface(ctrl)
+		mockDNSResources := mockclientset.NewMockDNSResources(ctrl)
+		s := &Service{
+			scope: &scope.ClusterScope{
+				Logger:  log,
+				Cluster: cluster,
+				MaasCluster: &infrav1beta1.MaasCluster{
+					Status: infrav1beta1.MaasClusterStatus{
+						Network: infrav1beta1.Network{DNSName: "a-abcde.b.com"},
+					},
+				},
+			},
+			maasClient: mockClientSetInterface,
+		}
+		mockClientSetInterface.EXPECT().DNSResources().Return(mockDNSResources)
+		mockDNSResources.EXPECT().List(context.Background(), gomock.Any()).Return(nil, nil)
+
+		g.Expect(s.DeleteDNS()).To(Succeed())
+	})
+
+	t.Run("dns name never set", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		mockClientSetInterface := mockclientset.NewMockClientSetInterface(ctrl)
+		s := &Service{
+			scope: &scope.ClusterScope{
+				Logger:      log,
+				Cluster:     cluster,
+				MaasCluster: &infrav1beta1.MaasCluster{},
+			},
+			maasClient: mockClientSetInterface,
+		}
+
+		g.Expect(s.DeleteDNS()).To(Succeed())
+	})
+}
+
+func TestReconcileDNSReadOnly(t *testing.T) {
+	cluster := &v1beta1.Cluster{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "a",
+		},
+	}
+
+	newReadOnlyScope := func(g *WithT, maasCluster *infrav1beta1.MaasCluster) *scope.ClusterScope {
+		scheme := runtime.NewScheme()
+		_ = infrav1beta1.AddToScheme(scheme)
+		clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+			Client:      fake.NewClientBuilder().WithScheme(scheme).Build(),
+			Logger:      klogr.New(),
+			Cluster:     cluster,
+			MaasCluster: maasCluster,
+			ReadOnly:    true,
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		return clusterScope
+	}
+
+	t.Run("dns name never set", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		mockClientSetInterface := mockclientset.NewMockClientSetInterface(ctrl)
+		maasCluster := &infrav1beta1.MaasCluster{
+			Spec: infrav1beta1.MaasClusterSpec{
+				DNSDomain: "b.com",
+			},
+		}
+		s := &Service{
+			scope:      newReadOnlyScope(g, maasCluster),
+			maasClient: mockClientSetInterface,
+		}
+
+		g.Expect(errors.Is(s.ReconcileDNS(), scope.ErrReadOnly)).To(BeTrue())
+		g.Expect(maasCluster.Status.Network.DNSName).To(BeEmpty())
+	})
+
+	t.Run("dns resource missing", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		mockClientSetInterface := mockclientset.NewMockClientSetInterface(ctrl)
+		mockDNSResources := mockclientset.NewMockDNSResources(ctrl)
+		maasCluster := &infrav1beta1.MaasCluster{
+			Status: infrav1beta1.MaasClusterStatus{
+				Network: infrav1beta1.Network{DNSName: "a-abcde.b.com"},
+			},
+		}
+		s := &Service{
+			scope:      newReadOnlyScope(g, maasCluster),
+			maasClient: mockClientSetInterface,
+		}
+		mockClientSetInterface.EXPECT().DNSResources().Return(mockDNSResources)
+		mockDNSResources.EXPECT().List(context.Background(), gomock.Any()).Return(nil, nil)
+
+		g.Expect(errors.Is(s.ReconcileDNS(), scope.ErrReadOnly)).To(BeTrue())
+	})
+}
+
+func TestAPIServerIsResponding(t *testing.T) {
+	log := klogr.New()
+
+	newService := func(port int32) *Service {
+		return &Service{
+			scope: &scope.ClusterScope{
+				Logger: log,
+				Cluster: &v1beta1.Cluster{
+					ObjectMeta: v1.ObjectMeta{
+						Name: "a",
+					},
+					Spec: v1beta1.ClusterSpec{
+						ClusterNetwork: &v1beta1.ClusterNetwork{APIServerPort: &port},
+					},
+				},
+				MaasCluster: &infrav1beta1.MaasCluster{},
+			},
+		}
+	}
+
+	t.Run("api server listening", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		g.Expect(err).ToNot(HaveOccurred())
+		defer l.Close()
+
+		s := newService(int32(l.Addr().(*net.TCPAddr).Port))
+		g.Expect(s.APIServerIsResponding("127.0.0.1", time.Second)).To(BeTrue())
+	})
+
+	t.Run("api server not listening", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		g.Expect(err).ToNot(HaveOccurred())
+		port := l.Addr().(*net.TCPAddr).Port
+		g.Expect(l.Close()).To(Succeed())
+
+		s := newService(int32(port))
+		g.Expect(s.APIServerIsResponding("127.0.0.1", time.Second)).To(BeFalse())
+	})
+}
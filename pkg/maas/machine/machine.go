@@ -2,14 +2,20 @@ package machine
 
 import (
 	"context"
+	"fmt"
 	"github.com/pkg/errors"
-	"github.com/prometheus/common/log"
 	infrav1beta1 "github.com/spectrocloud/cluster-api-provider-maas/api/v1beta1"
 	"github.com/spectrocloud/cluster-api-provider-maas/pkg/maas/scope"
 	"github.com/spectrocloud/maas-client-go/maasclient"
+	"net/http"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"strings"
 )
 
+// ErrNoCapacity is returned when no MaaS machine matches the allocation constraints
+var ErrNoCapacity = errors.New("no MaaS machine matches the allocation constraints")
+
 // Service manages the MaaS machine
 type Service struct {
 	scope      *scope.MachineScope
@@ -35,12 +41,19 @@ func (s *Service) GetMachine(systemID string) (*infrav1beta1.Machine, error) {
 	return machine, nil
 }
 
-func (s *Service) ReleaseMachine(systemID string) error {
+// ReleaseMachine releases the MaaS machine, recording the reason as release comment in the MaaS audit log
+func (s *Service) ReleaseMachine(systemID, reason string) error {
+	if s.scope.ReadOnly() {
+		s.scope.Info("Read-only mode; skipping machine release", "system-id", systemID)
+		return scope.ErrReadOnly
+	}
+
 	ctx := context.TODO()
 
 	_, err := s.maasClient.Machines().
 		Machine(systemID).
 		Releaser().
+		WithComment(reason).
 		Release(ctx)
 	if err != nil {
 		return errors.Wrapf(err, "Unable to release machine")
@@ -49,16 +62,64 @@ func (s *Service) ReleaseMachine(systemID string) error {
 	return nil
 }
 
-func (s *Service) DeployMachine(userDataB64 string) (_ *infrav1beta1.Machine, rerr error) {
-	ctx := context.TODO()
+// failureDomain returns the zone to allocate the machine in, if any
+func (s *Service) failureDomain() *string {
+	if s.scope.MaasMachine.Spec.FailureDomain != nil {
+		return s.scope.MaasMachine.Spec.FailureDomain
+	}
+	return s.scope.Machine.Spec.FailureDomain
+}
 
+// ReadyMachineCount returns the number of MaaS machines available for allocation (Ready state)
+// in the failure domain of the machine, or in all zones if it has none.
+// The resource pool and tags are passed as listing filters, but the MaaS client currently lists machines
+// without them, and its machines don't report CPU and memory. The count is then an upper bound, DeployMachine
+// returns ErrNoCapacity when the allocator finds no machine matching all the constraints.
+func (s *Service) ReadyMachineCount() (int, error) {
 	mm := s.scope.MaasMachine
+	failureDomain := s.failureDomain()
+
+	params := maasclient.ParamsBuilder()
+	if failureDomain != nil {
+		params.Set(maasclient.ZoneKey, *failureDomain)
+	}
+	if mm.Spec.ResourcePool != nil {
+		params.Set(maasclient.PoolLabel, *mm.Spec.ResourcePool)
+	}
+	for _, tag := range mm.Spec.Tags {
+		params.Add(maasclient.TagKey, tag)
+	}
 
-	failureDomain := mm.Spec.FailureDomain
-	if failureDomain == nil {
-		failureDomain = s.scope.Machine.Spec.FailureDomain
+	machines, err := s.maasClient.Machines().List(context.Background(), params)
+	if err != nil {
+		return 0, errors.Wrap(err, "Unable to list machines")
 	}
 
+	count := 0
+	for _, m := range machines {
+		if infrav1beta1.MachineState(m.State()) != infrav1beta1.MachineStateReady {
+			continue
+		}
+		if failureDomain != nil && m.Zone().Name() != *failureDomain {
+			continue
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+func (s *Service) DeployMachine(userDataB64 string) (_ *infrav1beta1.Machine, rerr error) {
+	if s.scope.ReadOnly() {
+		s.scope.Info("Read-only mode; skipping machine allocation and deploy")
+		return nil, scope.ErrReadOnly
+	}
+
+	ctx := context.TODO()
+
+	mm := s.scope.MaasMachine
+	failureDomain := s.failureDomain()
+
 	var m maasclient.Machine
 	var err error
 
@@ -82,7 +143,9 @@ func (s *Service) DeployMachine(userDataB64 string) (_ *infrav1beta1.Machine, re
 		}
 
 		m, err = allocator.Allocate(ctx)
-		if err != nil {
+		if err != nil && isConflict(err) {
+			return nil, errors.Wrap(ErrNoCapacity, err.Error())
+		} else if err != nil {
 			return nil, errors.Wrapf(err, "Unable to allocate machine")
 		}
 
@@ -96,6 +159,19 @@ func (s *Service) DeployMachine(userDataB64 string) (_ *infrav1beta1.Machine, re
 		if err != nil {
 			return nil, errors.Wrapf(err, "unable to find machine %s", *s.scope.GetInstanceID())
 		}
+
+		// A machine released by a forced redeploy has to be allocated again before it's deployed.
+		// It was in the Ready pool since its release, so another MaaS user may have allocated it first.
+		// A machine released outside of the provider isn't taken back.
+		if infrav1beta1.MachineState(m.State()) == infrav1beta1.MachineStateReady {
+			if conditions.GetReason(mm, infrav1beta1.MachineDeployedCondition) != infrav1beta1.MachineRedeployingReason {
+				return nil, errors.Errorf("machine %s was released outside of the provider", *s.scope.GetInstanceID())
+			}
+			m, err = s.maasClient.Machines().Allocator().WithSystemID(m.SystemID()).Allocate(ctx)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Unable to allocate machine %s", *s.scope.GetInstanceID())
+			}
+		}
 	}
 
 	s.scope.Info("Allocated machine", "system-id", m.SystemID())
@@ -103,10 +179,12 @@ func (s *Service) DeployMachine(userDataB64 string) (_ *infrav1beta1.Machine, re
 	defer func() {
 		if rerr != nil {
 			s.scope.Info("Attempting to release machine which failed to deploy")
-			_, err := m.Releaser().Release(ctx)
+			_, err := m.Releaser().
+				WithComment(fmt.Sprintf("capmaas: failed deploy %s/%s", s.scope.Cluster.Name, mm.Name)).
+				Release(ctx)
 			if err != nil {
 				// Is it right to NOT set rerr so we can see the original issue?
-				log.Error(err, "Unable to release properly")
+				s.scope.Error(err, "Unable to release properly", "system-id", m.SystemID())
 			}
 		}
 	}()
@@ -131,6 +209,11 @@ func (s *Service) DeployMachine(userDataB64 string) (_ *infrav1beta1.Machine, re
 	return fromSDKTypeToMachine(deployingM), nil
 }
 
+// isConflict returns if the MaaS API call failed with a 409 Conflict, e.g. no machine matches the allocation constraints
+func isConflict(err error) bool {
+	return strings.HasPrefix(err.Error(), fmt.Sprintf("status: %d,", http.StatusConflict))
+}
+
 func fromSDKTypeToMachine(m maasclient.Machine) *infrav1beta1.Machine {
 	machine := &infrav1beta1.Machine{
 		ID:               m.SystemID(),
@@ -158,6 +241,11 @@ func fromSDKTypeToMachine(m maasclient.Machine) *infrav1beta1.Machine {
 }
 
 func (s *Service) PowerOnMachine() error {
+	if s.scope.ReadOnly() {
+		s.scope.Info("Read-only mode; skipping machine power on")
+		return scope.ErrReadOnly
+	}
+
 	_, err := s.maasClient.Machines().Machine(s.scope.GetSystemID()).PowerManagerOn().WithPowerOnComment("maas provider power on").PowerOn(context.Background())
 	return err
 }
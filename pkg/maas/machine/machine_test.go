@@ -7,12 +7,19 @@ import (
 
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/klog/v2/klogr"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	infrav1beta1 "github.com/spectrocloud/cluster-api-provider-maas/api/v1beta1"
 	mockclientset "github.com/spectrocloud/cluster-api-provider-maas/pkg/maas/client/mock"
 	"github.com/spectrocloud/cluster-api-provider-maas/pkg/maas/scope"
+	"github.com/spectrocloud/maas-client-go/maasclient"
 )
 
 func TestMachine(t *testing.T) {
@@ -105,9 +112,10 @@ func TestMachine(t *testing.T) {
 		mockClientSetInterface.EXPECT().Machines().Return(mockMachines)
 		mockMachines.EXPECT().Machine("abc123").Return(mockMachine)
 		mockMachine.EXPECT().Releaser().Return(mockMachineReleaser)
-		mockMachineReleaser.EXPECT().Release(context.Background()).Return(mockMachine, nil)
+		mockMachineReleaser.EXPECT().WithComment("capmaas: delete a/m").Return(mockMachineReleaser)
+		mockMachineReleaser.EXPECT().Release(context.TODO()).Return(mockMachine, nil)
 
-		err := s.ReleaseMachine("abc123")
+		err := s.ReleaseMachine("abc123", "capmaas: delete a/m")
 		g.Expect(err).ToNot(HaveOccurred())
 	})
 
@@ -229,3 +237,211 @@ func TestMachine(t *testing.T) {
 	//	g.Expect(machine).To(BeNil())
 	//})
 }
+
+func TestMachineReadyMachineCount(t *testing.T) {
+	log := klogr.New()
+
+	newMachines := func(ctrl *gomock.Controller) []maasclient.Machine {
+		var machines []maasclient.Machine
+		for _, m := range []struct{ state, zone string }{
+			{"Ready", "zone1"},
+			{"Ready", "zone2"},
+			{"Deployed", "zone1"},
+			{"Ready", "zone1"},
+		} {
+			mockMachine := mockclientset.NewMockMachine(ctrl)
+			mockZone := mockclientset.NewMockZone(ctrl)
+			mockMachine.EXPECT().State().Return(m.state).AnyTimes()
+			mockMachine.EXPECT().Zone().Return(mockZone).AnyTimes()
+			mockZone.EXPECT().Name().Return(m.zone).AnyTimes()
+			machines = append(machines, mockMachine)
+		}
+		return machines
+	}
+
+	t.Run("ready machines in failure domain", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		mockClientSetInterface := mockclientset.NewMockClientSetInterface(ctrl)
+		mockMachines := mockclientset.NewMockMachines(ctrl)
+
+		s := &Service{
+			scope: &scope.MachineScope{
+				Logger:  log,
+				Machine: &v1beta1.Machine{},
+				MaasMachine: &infrav1beta1.MaasMachine{
+					Spec: infrav1beta1.MaasMachineSpec{
+						FailureDomain: pointer.String("zone1"),
+						ResourcePool:  pointer.String("pool1"),
+						Tags:          []string{"gpu", "ssd"},
+					},
+				},
+			},
+			maasClient: mockClientSetInterface,
+		}
+
+		params := maasclient.ParamsBuilder().
+			Set(maasclient.ZoneKey, "zone1").
+			Set(maasclient.PoolLabel, "pool1").
+			Add(maasclient.TagKey, "gpu").
+			Add(maasclient.TagKey, "ssd")
+		mockClientSetInterface.EXPECT().Machines().Return(mockMachines)
+		mockMachines.EXPECT().List(context.Background(), params).Return(newMachines(ctrl), nil)
+
+		count, err := s.ReadyMachineCount()
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(count).To(Equal(2))
+	})
+
+	t.Run("ready machines in all zones", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		mockClientSetInterface := mockclientset.NewMockClientSetInterface(ctrl)
+		mockMachines := mockclientset.NewMockMachines(ctrl)
+
+		s := &Service{
+			scope: &scope.MachineScope{
+				Logger:      log,
+				Machine:     &v1beta1.Machine{},
+				MaasMachine: &infrav1beta1.MaasMachine{},
+			},
+			maasClient: mockClientSetInterface,
+		}
+
+		mockClientSetInterface.EXPECT().Machines().Return(mockMachines)
+		mockMachines.EXPECT().List(context.Background(), gomock.Any()).Return(newMachines(ctrl), nil)
+
+		count, err := s.ReadyMachineCount()
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(count).To(Equal(3))
+	})
+}
+
+func TestMachineDeployNoCapacity(t *testing.T) {
+	g := NewGomegaWithT(t)
+	ctrl := gomock.NewController(t)
+	mockClientSetInterface := mockclientset.NewMockClientSetInterface(ctrl)
+	mockMachines := mockclientset.NewMockMachines(ctrl)
+	mockMachineAllocator := mockclientset.NewMockMachineAllocator(ctrl)
+
+	s := &Service{
+		scope: &scope.MachineScope{
+			Logger:  klogr.New(),
+			Machine: &v1beta1.Machine{},
+			MaasMachine: &infrav1beta1.MaasMachine{
+				Spec: infrav1beta1.MaasMachineSpec{
+					MinCPU:        pointer.Int(4),
+					MinMemoryInMB: pointer.Int(8192),
+				},
+			},
+		},
+		maasClient: mockClientSetInterface,
+	}
+
+	mockClientSetInterface.EXPECT().Machines().Return(mockMachines)
+	mockMachines.EXPECT().Allocator().Return(mockMachineAllocator)
+	mockMachineAllocator.EXPECT().WithCPUCount(4).Return(mockMachineAllocator)
+	mockMachineAllocator.EXPECT().WithMemory(8192).Return(mockMachineAllocator)
+	mockMachineAllocator.EXPECT().Allocate(context.TODO()).Return(nil, errors.New("status: 409, message: No available machine matches constraints"))
+
+	_, err := s.DeployMachine("")
+	g.Expect(errors.Is(err, ErrNoCapacity)).To(BeTrue())
+}
+
+func TestMachineDeployReleasedMachine(t *testing.T) {
+	log := klogr.New()
+	cluster := &v1beta1.Cluster{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "a",
+		},
+	}
+
+	newService := func(ctrl *gomock.Controller, maasMachine *infrav1beta1.MaasMachine) (*Service, *mockclientset.MockMachines) {
+		mockClientSetInterface := mockclientset.NewMockClientSetInterface(ctrl)
+		mockMachines := mockclientset.NewMockMachines(ctrl)
+		mockMachine := mockclientset.NewMockMachine(ctrl)
+
+		mockClientSetInterface.EXPECT().Machines().Return(mockMachines).AnyTimes()
+		mockMachines.EXPECT().Machine("abc123").Return(mockMachine)
+		mockMachine.EXPECT().Get(context.TODO()).Return(mockMachine, nil)
+		mockMachine.EXPECT().State().Return("Ready")
+		mockMachine.EXPECT().SystemID().Return("abc123").AnyTimes()
+
+		return &Service{
+			scope: &scope.MachineScope{
+				Logger:      log,
+				Cluster:     cluster,
+				Machine:     &v1beta1.Machine{},
+				MaasMachine: maasMachine,
+			},
+			maasClient: mockClientSetInterface,
+		}, mockMachines
+	}
+
+	t.Run("machine released outside of the provider", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		s, _ := newService(ctrl, &infrav1beta1.MaasMachine{
+			Spec: infrav1beta1.MaasMachineSpec{
+				ProviderID: pointer.String("maas:///zone1/abc123"),
+			},
+		})
+
+		_, err := s.DeployMachine("")
+		g.Expect(err).To(MatchError(ContainSubstring("released outside of the provider")))
+	})
+
+	t.Run("machine released by a forced redeploy", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		maasMachine := &infrav1beta1.MaasMachine{
+			Spec: infrav1beta1.MaasMachineSpec{
+				ProviderID: pointer.String("maas:///zone1/abc123"),
+			},
+		}
+		conditions.MarkFalse(maasMachine, infrav1beta1.MachineDeployedCondition, infrav1beta1.MachineRedeployingReason, v1beta1.ConditionSeverityInfo, "")
+		s, mockMachines := newService(ctrl, maasMachine)
+
+		mockMachineAllocator := mockclientset.NewMockMachineAllocator(ctrl)
+		mockMachines.EXPECT().Allocator().Return(mockMachineAllocator)
+		mockMachineAllocator.EXPECT().WithSystemID("abc123").Return(mockMachineAllocator)
+		mockMachineAllocator.EXPECT().Allocate(context.TODO()).Return(nil, errors.New("status: 409, message: taken"))
+
+		_, err := s.DeployMachine("")
+		g.Expect(err).To(MatchError(ContainSubstring("Unable to allocate machine abc123")))
+	})
+}
+
+func TestMachineReadOnly(t *testing.T) {
+	g := NewGomegaWithT(t)
+	ctrl := gomock.NewController(t)
+	mockClientSetInterface := mockclientset.NewMockClientSetInterface(ctrl)
+
+	scheme := runtime.NewScheme()
+	_ = infrav1beta1.AddToScheme(scheme)
+	maasCluster := &infrav1beta1.MaasCluster{}
+	clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Client:      fake.NewClientBuilder().WithScheme(scheme).Build(),
+		Logger:      klogr.New(),
+		Cluster:     &v1beta1.Cluster{},
+		MaasCluster: maasCluster,
+		ReadOnly:    true,
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	s := &Service{
+		scope: &scope.MachineScope{
+			Logger:       klogr.New(),
+			ClusterScope: clusterScope,
+			Machine:      &v1beta1.Machine{},
+			MaasMachine:  &infrav1beta1.MaasMachine{Spec: infrav1beta1.MaasMachineSpec{SystemID: pointer.String("abc123")}},
+		},
+		maasClient: mockClientSetInterface,
+	}
+
+	// No MaaS call is expected on the client mock
+	g.Expect(errors.Is(s.ReleaseMachine("abc123", "capmaas: delete a/m"), scope.ErrReadOnly)).To(BeTrue())
+	g.Expect(errors.Is(s.PowerOnMachine(), scope.ErrReadOnly)).To(BeTrue())
+	_, err = s.DeployMachine("")
+	g.Expect(errors.Is(err, scope.ErrReadOnly)).To(BeTrue())
+}
@@ -17,8 +17,13 @@ limitations under the License.
 package scope
 
 import (
+	"context"
 	"github.com/spectrocloud/maas-client-go/maasclient"
+	"k8s.io/client-go/kubernetes"
 	"os"
+	"sigs.k8s.io/cluster-api/controllers/remote"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sync"
 )
 
 // NewMaasClient creates a new MaaS client for a given session
@@ -38,3 +43,48 @@ func NewMaasClient(_ *ClusterScope) maasclient.ClientSetInterface {
 	maasClient := maasclient.NewAuthenticatedClientSet(maasEndpoint, maasAPIKey)
 	return maasClient
 }
+
+// WorkloadClientsets caches a clientset per workload cluster, for the calls the cached ClusterCacheTracker
+// client can't make (listing pods by node, evictions)
+type WorkloadClientsets struct {
+	lock       sync.Mutex
+	clientsets map[client.ObjectKey]kubernetes.Interface
+}
+
+// NewWorkloadClientsets returns an empty workload cluster clientset cache
+func NewWorkloadClientsets() *WorkloadClientsets {
+	return &WorkloadClientsets{
+		clientsets: map[client.ObjectKey]kubernetes.Interface{},
+	}
+}
+
+// Get returns the clientset of the workload cluster, creating it from the cluster kubeconfig secret if needed
+func (w *WorkloadClientsets) Get(ctx context.Context, c client.Client, controllerName string, cluster client.ObjectKey) (kubernetes.Interface, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if clientset, ok := w.clientsets[cluster]; ok {
+		return clientset, nil
+	}
+
+	restConfig, err := remote.RESTConfig(ctx, controllerName, c, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	w.clientsets[cluster] = clientset
+	return clientset, nil
+}
+
+// Delete drops the clientset of the workload cluster, e.g. when its credentials were rotated
+func (w *WorkloadClientsets) Delete(cluster client.ObjectKey) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	delete(w.clientsets, cluster)
+}
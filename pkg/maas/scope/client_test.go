@@ -17,8 +17,12 @@ limitations under the License.
 package scope
 
 import (
+	"context"
 	"github.com/onsi/gomega"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
 	"os"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"testing"
 )
 
@@ -54,3 +58,30 @@ func TestNewMaasClient(t *testing.T) {
 		g.Expect(client).ToNot(gomega.BeNil())
 	})
 }
+
+func TestWorkloadClientsets(t *testing.T) {
+	cluster := client.ObjectKey{Namespace: "default", Name: "cluster"}
+
+	t.Run("cached clientset", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		clientsets := NewWorkloadClientsets()
+		clientset := k8sfake.NewSimpleClientset()
+		clientsets.clientsets[cluster] = clientset
+
+		cached, err := clientsets.Get(context.TODO(), fake.NewClientBuilder().Build(), "test", cluster)
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		g.Expect(cached).To(gomega.BeIdenticalTo(clientset))
+	})
+
+	t.Run("deleted clientset is recreated", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		clientsets := NewWorkloadClientsets()
+		clientsets.clientsets[cluster] = k8sfake.NewSimpleClientset()
+		clientsets.Delete(cluster)
+
+		// there's no kubeconfig secret to recreate it from
+		_, err := clientsets.Get(context.TODO(), fake.NewClientBuilder().Build(), "test", cluster)
+		g.Expect(err).To(gomega.HaveOccurred())
+		g.Expect(clientsets.clientsets).To(gomega.BeEmpty())
+	})
+}
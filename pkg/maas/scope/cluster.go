@@ -50,6 +50,9 @@ type ClusterScopeParams struct {
 	ControllerName      string
 	Tracker             *remote.ClusterCacheTracker
 	ClusterEventChannel chan event.GenericEvent
+
+	// ReadOnly skips all the mutating MaaS calls
+	ReadOnly bool
 }
 
 // ClusterScope defines the basic context for an actuator to operate upon.
@@ -63,8 +66,12 @@ type ClusterScope struct {
 	controllerName      string
 	tracker             *remote.ClusterCacheTracker
 	clusterEventChannel chan event.GenericEvent
+	readOnly            bool
 }
 
+// ErrReadOnly is returned by the MaaS services when a mutating call is skipped in read-only mode
+var ErrReadOnly = errors.New("read-only mode; skipped mutating MaaS call")
+
 // NewClusterScope creates a new Scope from the supplied parameters.
 // This is meant to be called for each reconcile iteration.
 func NewClusterScope(params ClusterScopeParams) (*ClusterScope, error) {
@@ -74,7 +81,7 @@ func NewClusterScope(params ClusterScopeParams) (*ClusterScope, error) {
 		return nil, errors.Wrap(err, "failed to init patch helper")
 	}
 	return &ClusterScope{
-		Logger:              params.Logger,
+		Logger:              params.Logger.WithValues("cluster", params.Cluster.Name),
 		client:              params.Client,
 		Cluster:             params.Cluster,
 		MaasCluster:         params.MaasCluster,
@@ -82,6 +89,7 @@ func NewClusterScope(params ClusterScopeParams) (*ClusterScope, error) {
 		controllerName:      params.ControllerName,
 		tracker:             params.Tracker,
 		clusterEventChannel: params.ClusterEventChannel,
+		readOnly:            params.ReadOnly,
 	}, nil
 }
 
@@ -105,6 +113,7 @@ func (s *ClusterScope) PatchObject() error {
 			clusterv1.ReadyCondition,
 			infrav1beta1.DNSReadyCondition,
 			infrav1beta1.APIServerAvailableCondition,
+			infrav1beta1.FailureDomainsValidCondition,
 		}},
 	)
 }
@@ -114,19 +123,33 @@ func (s *ClusterScope) Close() error {
 	return s.PatchObject()
 }
 
-// APIServerPort returns the APIServerPort to use when creating the load balancer.
+// APIServerPort returns the APIServerPort to use when creating the load balancer and probing the API server.
+// The port of an already set control plane endpoint takes precedence over the cluster network port.
 func (s *ClusterScope) APIServerPort() int {
+	if s.Cluster.Spec.ControlPlaneEndpoint.Port != 0 {
+		return int(s.Cluster.Spec.ControlPlaneEndpoint.Port)
+	}
 	if s.Cluster.Spec.ClusterNetwork != nil && s.Cluster.Spec.ClusterNetwork.APIServerPort != nil {
 		return int(*s.Cluster.Spec.ClusterNetwork.APIServerPort)
 	}
 	return 6443
 }
 
+// ReadOnly returns true if mutating MaaS calls have to be skipped
+func (s *ClusterScope) ReadOnly() bool {
+	return s.readOnly
+}
+
 // SetDNSName sets the Network systemID in spec.
 func (s *ClusterScope) SetDNSName(dnsName string) {
 	s.MaasCluster.Status.Network.DNSName = dnsName
 }
 
+// SetDNSAttachedIPs sets the IPs attached to the DNS resource in status.
+func (s *ClusterScope) SetDNSAttachedIPs(ips []string) {
+	s.MaasCluster.Status.Network.AttachedIPs = ips
+}
+
 // GetDNSName sets the Network systemID in spec.
 // This can't do a lookup on Status.Network.DNSDomain name since it's derviced from here
 func (s *ClusterScope) GetDNSName() string {
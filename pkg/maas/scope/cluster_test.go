@@ -87,4 +87,17 @@ func TestNewCluster(t *testing.T) {
 		dnsLengh := len("dns-test-") + DnsSuffixLength + len(".maas.com")
 		g.Expect(len(scope.GetDNSName())).To(gomega.Equal(dnsLengh))
 	})
+	t.Run("api server port", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		clusterCopy := cluster.DeepCopy()
+		scope := &ClusterScope{Cluster: clusterCopy, MaasCluster: maasCluster.DeepCopy()}
+		g.Expect(scope.APIServerPort()).To(gomega.Equal(6443))
+
+		port := int32(8443)
+		clusterCopy.Spec.ClusterNetwork = &v1beta1.ClusterNetwork{APIServerPort: &port}
+		g.Expect(scope.APIServerPort()).To(gomega.Equal(8443))
+
+		clusterCopy.Spec.ControlPlaneEndpoint = v1beta1.APIEndpoint{Host: "a.maas.com", Port: 9443}
+		g.Expect(scope.APIServerPort()).To(gomega.Equal(9443))
+	})
 }
@@ -23,8 +23,19 @@ import (
 	"github.com/pkg/errors"
 	infrav1beta1 "github.com/spectrocloud/cluster-api-provider-maas/api/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+	bootstrapapi "k8s.io/cluster-bootstrap/token/api"
+	bootstraputil "k8s.io/cluster-bootstrap/token/util"
 	"k8s.io/utils/pointer"
+	"regexp"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/noderefutil"
 	"sigs.k8s.io/cluster-api/controllers/remote"
@@ -33,6 +44,22 @@ import (
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrBootstrapDataSecretNotFound is returned when the bootstrap data secret of the Machine doesn't exist
+	ErrBootstrapDataSecretNotFound = errors.New("bootstrap data secret not found")
+
+	// ErrBootstrapDataKeyMissing is returned when the bootstrap data secret has no value key
+	ErrBootstrapDataKeyMissing = errors.New("bootstrap data secret value key is missing")
+
+	// ErrBootstrapDataEmpty is returned when the value of the bootstrap data secret is empty
+	ErrBootstrapDataEmpty = errors.New("bootstrap data secret value is empty")
+
+	// bootstrapTokenRegexp matches the kubeadm join token in the bootstrap data
+	bootstrapTokenRegexp = regexp.MustCompile(`\b([a-z0-9]{6})\.([a-z0-9]{16})\b`)
 )
 
 // MachineScopeParams defines the input parameters used to create a new Scope.
@@ -46,6 +73,9 @@ type MachineScopeParams struct {
 	ControllerName string
 
 	Tracker *remote.ClusterCacheTracker
+
+	// Clientsets caches the workload cluster clientsets, a new clientset is created per call when nil
+	Clientsets *WorkloadClientsets
 }
 
 // MachineScope defines the basic context for an actuator to operate upon.
@@ -62,6 +92,7 @@ type MachineScope struct {
 
 	controllerName string
 	tracker        *remote.ClusterCacheTracker
+	clientsets     *WorkloadClientsets
 }
 
 // NewMachineScope creates a new Scope from the supplied parameters.
@@ -72,8 +103,15 @@ func NewMachineScope(params MachineScopeParams) (*MachineScope, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to init patch helper")
 	}
+
+	// Always carry the cluster and machine (and MaaS system-id once known) so log lines can be correlated
+	logger := params.Logger.WithValues("cluster", params.Cluster.Name, "machine", params.Machine.Name)
+	if params.MaasMachine.Spec.SystemID != nil {
+		logger = logger.WithValues("system-id", *params.MaasMachine.Spec.SystemID)
+	}
+
 	return &MachineScope{
-		Logger:         params.Logger,
+		Logger:         logger,
 		Machine:        params.Machine,
 		MaasMachine:    params.MaasMachine,
 		Cluster:        params.Cluster,
@@ -81,6 +119,7 @@ func NewMachineScope(params MachineScopeParams) (*MachineScope, error) {
 		patchHelper:    helper,
 		client:         params.Client,
 		tracker:        params.Tracker,
+		clientsets:     params.Clientsets,
 		controllerName: params.ControllerName,
 	}, nil
 }
@@ -170,6 +209,11 @@ func (m *MachineScope) GetInstanceID() *string {
 	return pointer.StringPtr(parsed.ID())
 }
 
+// ReadOnly returns true if mutating MaaS calls have to be skipped
+func (m *MachineScope) ReadOnly() bool {
+	return m.ClusterScope != nil && m.ClusterScope.ReadOnly()
+}
+
 // GetProviderID returns the MaasMachine providerID from the spec.
 func (m *MachineScope) GetProviderID() string {
 	if m.MaasMachine.Spec.ProviderID != nil {
@@ -178,12 +222,65 @@ func (m *MachineScope) GetProviderID() string {
 	return ""
 }
 
-// SetProviderID sets the MaasMachine providerID in spec.
+// SetProviderID sets the MaasMachine providerID in spec, using the providerID format of the cluster.
 func (m *MachineScope) SetProviderID(systemID, availabilityZone string) {
 	providerID := fmt.Sprintf("maas:///%s/%s", availabilityZone, systemID)
+	if m.ClusterScope != nil && m.ClusterScope.MaasCluster.Spec.ProviderIDFormat == infrav1beta1.ProviderIDFormatSystemID {
+		providerID = fmt.Sprintf("maas://%s", systemID)
+	}
 	m.MaasMachine.Spec.ProviderID = pointer.StringPtr(providerID)
 }
 
+// GetMachineTemplateDefaults returns the defaults of the MaasMachineTemplate the MaasMachine was cloned from, if any.
+func (m *MachineScope) GetMachineTemplateDefaults() (*infrav1beta1.MaasMachineTemplateDefaults, error) {
+	name, ok := m.MaasMachine.Annotations[clusterv1.TemplateClonedFromNameAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	templateGroupKind := infrav1beta1.GroupVersion.WithKind("MaasMachineTemplate").GroupKind()
+	if m.MaasMachine.Annotations[clusterv1.TemplateClonedFromGroupKindAnnotation] != templateGroupKind.String() {
+		return nil, nil
+	}
+
+	template := &infrav1beta1.MaasMachineTemplate{}
+	key := types.NamespacedName{Namespace: m.MaasMachine.Namespace, Name: name}
+	if err := m.client.Get(context.TODO(), key, template); err != nil {
+		if apierrors.IsNotFound(err) {
+			// The template of an old MachineSet can be gone already
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get MaasMachineTemplate %s", key)
+	}
+
+	return template.Spec.Defaults, nil
+}
+
+// ApplyTemplateDefaults merges the template defaults into the MaasMachine spec.
+// The defaults tags are appended to the machine tags; the default failure domain is only used when neither the
+// MaasMachine nor the Machine has one, and the default resource pool when the MaasMachine has none.
+func (m *MachineScope) ApplyTemplateDefaults(defaults *infrav1beta1.MaasMachineTemplateDefaults) {
+	if defaults == nil {
+		return
+	}
+
+	spec := &m.MaasMachine.Spec
+	tags := sets.NewString(spec.Tags...)
+	for _, tag := range defaults.Tags {
+		if !tags.Has(tag) {
+			tags.Insert(tag)
+			spec.Tags = append(spec.Tags, tag)
+		}
+	}
+
+	if spec.FailureDomain == nil && m.Machine.Spec.FailureDomain == nil && defaults.FailureDomain != nil {
+		spec.FailureDomain = pointer.StringPtr(*defaults.FailureDomain)
+	}
+
+	if spec.ResourcePool == nil && defaults.ResourcePool != nil {
+		spec.ResourcePool = pointer.StringPtr(*defaults.ResourcePool)
+	}
+}
+
 // SetFailureDomain sets the MaasMachine systemID in spec.
 func (m *MachineScope) SetFailureDomain(availabilityZone string) {
 	m.MaasMachine.Spec.FailureDomain = pointer.StringPtr(availabilityZone)
@@ -211,6 +308,11 @@ func (m *MachineScope) SetPowered(powered bool) {
 	m.MaasMachine.Status.MachinePowered = powered
 }
 
+// ManagePower returns if the controller should power on deployed machines which are powered off
+func (m *MachineScope) ManagePower() bool {
+	return m.MaasMachine.Spec.ManagePower == nil || *m.MaasMachine.Spec.ManagePower
+}
+
 // GetMachineHostname retrns the hostname
 func (m *MachineScope) GetMachineHostname() string {
 	if m.MaasMachine.Status.Hostname != nil {
@@ -250,17 +352,93 @@ func (m *MachineScope) GetRawBootstrapData() ([]byte, error) {
 	secret := &corev1.Secret{}
 	key := types.NamespacedName{Namespace: namespace, Name: *m.Machine.Spec.Bootstrap.DataSecretName}
 	if err := m.client.Get(context.TODO(), key, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, errors.Wrapf(ErrBootstrapDataSecretNotFound, "secret %s", key)
+		}
 		return nil, errors.Wrapf(err, "failed to retrieve bootstrap data secret for MaasMachine %s/%s", namespace, m.Machine.Name)
 	}
 
 	value, ok := secret.Data["value"]
 	if !ok {
-		return nil, errors.New("error retrieving bootstrap data: secret value key is missing")
+		return nil, errors.Wrapf(ErrBootstrapDataKeyMissing, "secret %s", key)
+	}
+
+	if len(value) == 0 {
+		return nil, errors.Wrapf(ErrBootstrapDataEmpty, "secret %s", key)
 	}
 
 	return value, nil
 }
 
+// BootstrapTokenUsable returns if the kubeadm join token in the bootstrap data still exists in the workload cluster
+// and doesn't expire within minTTL. The bootstrap provider stops refreshing the token once the node joined, so
+// redeploying with the same bootstrap data only works while it's still valid. Bootstrap data without a token is usable.
+func (m *MachineScope) BootstrapTokenUsable(minTTL time.Duration) (bool, error) {
+	data, err := m.GetRawBootstrapData()
+	if err != nil {
+		return false, err
+	}
+
+	ctx := context.TODO()
+	clientset, err := m.workloadClientset(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	usable, err := bootstrapTokenUsable(ctx, clientset, data, time.Now().Add(minTTL))
+	if err != nil {
+		m.forgetWorkloadClientset(err)
+	}
+	return usable, err
+}
+
+// bootstrapTokenUsable returns if the join token in the bootstrap data exists and is still valid at the given time
+func bootstrapTokenUsable(ctx context.Context, clientset kubernetes.Interface, data []byte, at time.Time) (bool, error) {
+	match := bootstrapTokenRegexp.FindSubmatch(data)
+	if match == nil {
+		return true, nil
+	}
+
+	tokenID := string(match[1])
+	secret, err := clientset.CoreV1().Secrets(metav1.NamespaceSystem).Get(ctx, bootstraputil.BootstrapTokenSecretName(tokenID), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Wrapf(err, "failed to get bootstrap token %s", tokenID)
+	}
+
+	expiration, ok := secret.Data[bootstrapapi.BootstrapTokenExpirationKey]
+	if !ok {
+		return true, nil
+	}
+
+	expiresAt, err := time.Parse(time.RFC3339, string(expiration))
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse bootstrap token %s expiration", tokenID)
+	}
+
+	return expiresAt.After(at), nil
+}
+
+// IsNodeRegistered returns if the node of the machine exists in the workload cluster
+func (m *MachineScope) IsNodeRegistered() (bool, error) {
+	ctx := context.TODO()
+	remoteClient, err := m.tracker.GetClient(ctx, util.ObjectKey(m.Cluster))
+	if err != nil {
+		return false, err
+	}
+
+	node := &corev1.Node{}
+	if err := remoteClient.Get(ctx, client.ObjectKey{Name: m.GetMachineHostname()}, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
 // SetNodeProviderID patches the node with the ID
 func (m *MachineScope) SetNodeProviderID() error {
 	ctx := context.TODO()
@@ -288,3 +466,276 @@ func (m *MachineScope) SetNodeProviderID() error {
 
 	return patchHelper.Patch(ctx, node)
 }
+
+// NodeLabels returns the MaaS derived labels to set on the workload cluster node, matching the NodeLabelPrefixes.
+// Tags which can't be represented as a label are skipped.
+func (m *MachineScope) NodeLabels() map[string]string {
+	labels := map[string]string{}
+	if len(m.MaasMachine.Spec.NodeLabelPrefixes) == 0 {
+		return labels
+	}
+
+	if fd := m.MaasMachine.Spec.FailureDomain; fd != nil && len(validation.IsValidLabelValue(*fd)) == 0 {
+		labels[infrav1beta1.NodeZoneLabel] = *fd
+	}
+
+	if rp := m.MaasMachine.Spec.ResourcePool; rp != nil && len(validation.IsValidLabelValue(*rp)) == 0 {
+		labels[infrav1beta1.NodeResourcePoolLabel] = *rp
+	}
+
+	for _, tag := range m.MaasMachine.Spec.Tags {
+		key := infrav1beta1.NodeTagLabelPrefix + tag
+		if len(validation.IsQualifiedName(key)) == 0 {
+			labels[key] = ""
+		}
+	}
+
+	for key := range labels {
+		allowed := false
+		for _, prefix := range m.MaasMachine.Spec.NodeLabelPrefixes {
+			if strings.HasPrefix(key, prefix) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			delete(labels, key)
+		}
+	}
+
+	return labels
+}
+
+// SetNodeLabels patches the node with the MaaS derived labels and annotations,
+// and removes the labels it set before which no longer apply
+func (m *MachineScope) SetNodeLabels() error {
+	ctx := context.TODO()
+	remoteClient, err := m.tracker.GetClient(ctx, util.ObjectKey(m.Cluster))
+	if err != nil {
+		return err
+	}
+
+	node := &corev1.Node{}
+	if err := remoteClient.Get(ctx, client.ObjectKey{Name: m.GetMachineHostname()}, node); err != nil {
+		return err
+	}
+
+	patchHelper, err := patch.NewHelper(node, remoteClient)
+	if err != nil {
+		return err
+	}
+
+	systemID := ""
+	if len(m.MaasMachine.Spec.NodeLabelPrefixes) > 0 {
+		systemID = m.GetSystemID()
+	}
+	if !applyNodeLabels(node, m.NodeLabels(), systemID) {
+		return nil
+	}
+
+	return patchHelper.Patch(ctx, node)
+}
+
+// applyNodeLabels sets the labels on the node, removes the labels listed in its managed labels annotation
+// which aren't set anymore, and sets the system id annotation if any. It returns true if the node changed.
+func applyNodeLabels(node *corev1.Node, labels map[string]string, systemID string) bool {
+	changed := false
+
+	if managed, ok := node.Annotations[infrav1beta1.NodeManagedLabelsAnnotation]; ok {
+		for _, k := range strings.Split(managed, ",") {
+			if _, ok := labels[k]; ok {
+				continue
+			}
+			if _, ok := node.Labels[k]; ok {
+				delete(node.Labels, k)
+				changed = true
+			}
+		}
+	}
+
+	for k, v := range labels {
+		if val, ok := node.Labels[k]; !ok || val != v {
+			if node.Labels == nil {
+				node.Labels = map[string]string{}
+			}
+			node.Labels[k] = v
+			changed = true
+		}
+	}
+
+	setAnnotation := func(key, value string) {
+		if val, ok := node.Annotations[key]; value == "" && ok {
+			delete(node.Annotations, key)
+			changed = true
+		} else if value != "" && val != value {
+			if node.Annotations == nil {
+				node.Annotations = map[string]string{}
+			}
+			node.Annotations[key] = value
+			changed = true
+		}
+	}
+	setAnnotation(infrav1beta1.NodeManagedLabelsAnnotation, strings.Join(sets.StringKeySet(labels).List(), ","))
+	if systemID != "" {
+		setAnnotation(infrav1beta1.NodeSystemIDAnnotation, systemID)
+	}
+
+	return changed
+}
+
+// CordonNode marks the node of the machine unschedulable, if it's registered
+func (m *MachineScope) CordonNode() error {
+	_, err := m.cordonNode(context.TODO())
+	return err
+}
+
+// cordonNode marks the node of the machine unschedulable and returns its name, or an empty name if it's not registered
+func (m *MachineScope) cordonNode(ctx context.Context) (string, error) {
+	remoteClient, err := m.tracker.GetClient(ctx, util.ObjectKey(m.Cluster))
+	if err != nil {
+		return "", err
+	}
+
+	nodeName := m.GetMachineHostname()
+	if m.Machine.Status.NodeRef != nil {
+		nodeName = m.Machine.Status.NodeRef.Name
+	}
+
+	node := &corev1.Node{}
+	if err := remoteClient.Get(ctx, client.ObjectKey{Name: nodeName}, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	if !node.Spec.Unschedulable {
+		patchHelper, err := patch.NewHelper(node, remoteClient)
+		if err != nil {
+			return "", err
+		}
+		node.Spec.Unschedulable = true
+		if err := patchHelper.Patch(ctx, node); err != nil {
+			return "", errors.Wrapf(err, "failed to cordon node %s", nodeName)
+		}
+	}
+
+	return nodeName, nil
+}
+
+// DrainNode cordons the node of the machine and evicts its pods, honoring PodDisruptionBudgets.
+// It returns true once no pods needing eviction are left on the node.
+func (m *MachineScope) DrainNode() (bool, error) {
+	if _, ok := m.Machine.Annotations[clusterv1.ExcludeNodeDrainingAnnotation]; ok {
+		m.Info("Node draining excluded by annotation", "annotation", clusterv1.ExcludeNodeDrainingAnnotation)
+		return true, nil
+	}
+
+	ctx := context.TODO()
+	nodeName, err := m.cordonNode(ctx)
+	if err != nil {
+		return false, err
+	}
+	if nodeName == "" {
+		return true, nil
+	}
+
+	// The cached tracker client can't list pods by node nor evict them, so use a clientset for those
+	clientset, err := m.workloadClientset(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	pods, err := clientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+	})
+	if err != nil {
+		m.forgetWorkloadClientset(err)
+		return false, errors.Wrapf(err, "failed to list pods on node %s", nodeName)
+	}
+
+	pending := 0
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !podNeedsEviction(pod) {
+			continue
+		}
+
+		pending++
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		if err := evictPod(ctx, clientset, pod); err != nil {
+			switch {
+			case apierrors.IsNotFound(err):
+				pending--
+			case apierrors.IsTooManyRequests(err):
+				// Eviction is blocked by a PodDisruptionBudget; retry on the next reconcile
+				m.V(2).Info("Pod eviction blocked by disruption budget", "pod", client.ObjectKeyFromObject(pod))
+			default:
+				m.forgetWorkloadClientset(err)
+				return false, errors.Wrapf(err, "failed to evict pod %s/%s", pod.Namespace, pod.Name)
+			}
+		}
+	}
+
+	return pending == 0, nil
+}
+
+// workloadClientset returns the clientset of the workload cluster, from the cache when there's one
+func (m *MachineScope) workloadClientset(ctx context.Context) (kubernetes.Interface, error) {
+	if m.clientsets == nil {
+		return NewWorkloadClientsets().Get(ctx, m.client, m.controllerName, util.ObjectKey(m.Cluster))
+	}
+	return m.clientsets.Get(ctx, m.client, m.controllerName, util.ObjectKey(m.Cluster))
+}
+
+// forgetWorkloadClientset drops the cached workload cluster clientset when its credentials are rejected
+func (m *MachineScope) forgetWorkloadClientset(err error) {
+	if m.clientsets != nil && apierrors.IsUnauthorized(err) {
+		m.clientsets.Delete(util.ObjectKey(m.Cluster))
+	}
+}
+
+// evictPod evicts the pod through the policy/v1 eviction API, falling back to policy/v1beta1 on workload clusters
+// which don't serve it yet (Kubernetes < 1.22). A NotFound error is only returned once the pod is gone.
+func evictPod(ctx context.Context, clientset kubernetes.Interface, pod *corev1.Pod) error {
+	objectMeta := metav1.ObjectMeta{
+		Name:      pod.Name,
+		Namespace: pod.Namespace,
+	}
+
+	err := clientset.PolicyV1().Evictions(pod.Namespace).Evict(ctx, &policyv1.Eviction{ObjectMeta: objectMeta})
+	if !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	// NotFound is returned both for a deleted pod and for an eviction API version which isn't served
+	if _, getErr := clientset.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{}); getErr != nil {
+		if apierrors.IsNotFound(getErr) {
+			return err
+		}
+		return getErr
+	}
+
+	return clientset.PolicyV1beta1().Evictions(pod.Namespace).Evict(ctx, &policyv1beta1.Eviction{ObjectMeta: objectMeta})
+}
+
+// podNeedsEviction returns if the pod has to be evicted for the node to be drained.
+// DaemonSet and mirror pods are left alone, as are pods which already completed.
+func podNeedsEviction(pod *corev1.Pod) bool {
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return false
+	}
+
+	if _, ok := pod.Annotations[corev1.MirrorPodAnnotationKey]; ok {
+		return false
+	}
+
+	if owner := metav1.GetControllerOf(pod); owner != nil && owner.Kind == "DaemonSet" {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,512 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/klog/v2/klogr"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1beta1 "github.com/spectrocloud/cluster-api-provider-maas/api/v1beta1"
+)
+
+func TestMachineNodeLabels(t *testing.T) {
+	t.Run("node labels from maas machine", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		scope := &MachineScope{
+			MaasMachine: &infrav1beta1.MaasMachine{
+				Spec: infrav1beta1.MaasMachineSpec{
+					FailureDomain:     pointer.String("zone1"),
+					ResourcePool:      pointer.String("pool1"),
+					Tags:              []string{"gpu", "invalid tag"},
+					NodeLabelPrefixes: []string{"maas.io/"},
+				},
+			},
+		}
+
+		g.Expect(scope.NodeLabels()).To(gomega.Equal(map[string]string{
+			infrav1beta1.NodeZoneLabel:              "zone1",
+			infrav1beta1.NodeResourcePoolLabel:      "pool1",
+			infrav1beta1.NodeTagLabelPrefix + "gpu": "",
+		}))
+	})
+
+	t.Run("node labels matching prefixes", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		scope := &MachineScope{
+			MaasMachine: &infrav1beta1.MaasMachine{
+				Spec: infrav1beta1.MaasMachineSpec{
+					FailureDomain:     pointer.String("zone1"),
+					ResourcePool:      pointer.String("pool1"),
+					Tags:              []string{"gpu"},
+					NodeLabelPrefixes: []string{infrav1beta1.NodeZoneLabel, infrav1beta1.NodeTagLabelPrefix},
+				},
+			},
+		}
+
+		g.Expect(scope.NodeLabels()).To(gomega.Equal(map[string]string{
+			infrav1beta1.NodeZoneLabel:              "zone1",
+			infrav1beta1.NodeTagLabelPrefix + "gpu": "",
+		}))
+	})
+
+	t.Run("no node labels without prefixes", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		scope := &MachineScope{
+			MaasMachine: &infrav1beta1.MaasMachine{
+				Spec: infrav1beta1.MaasMachineSpec{
+					FailureDomain: pointer.String("zone1"),
+				},
+			},
+		}
+
+		g.Expect(scope.NodeLabels()).To(gomega.BeEmpty())
+	})
+
+	t.Run("no node labels without placement", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		scope := &MachineScope{
+			MaasMachine: &infrav1beta1.MaasMachine{},
+		}
+
+		g.Expect(scope.NodeLabels()).To(gomega.BeEmpty())
+	})
+}
+
+func TestPodNeedsEviction(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	isController := true
+
+	tests := []struct {
+		name string
+		pod  *corev1.Pod
+		want bool
+	}{
+		{
+			name: "regular pod",
+			pod:  &corev1.Pod{},
+			want: true,
+		},
+		{
+			name: "completed pod",
+			pod: &corev1.Pod{
+				Status: corev1.PodStatus{Phase: corev1.PodSucceeded},
+			},
+			want: false,
+		},
+		{
+			name: "mirror pod",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{corev1.MirrorPodAnnotationKey: "hash"},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "daemonset pod",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					OwnerReferences: []metav1.OwnerReference{{Kind: "DaemonSet", Name: "ds", Controller: &isController}},
+				},
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g.Expect(podNeedsEviction(tt.pod)).To(gomega.Equal(tt.want))
+		})
+	}
+}
+
+func TestApplyNodeLabels(t *testing.T) {
+	t.Run("labels and annotations added", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		node := &corev1.Node{}
+
+		g.Expect(applyNodeLabels(node, map[string]string{"maas.io/zone": "zone1", "maas.io/tag-gpu": ""}, "abc123")).To(gomega.BeTrue())
+		g.Expect(node.Labels).To(gomega.Equal(map[string]string{"maas.io/zone": "zone1", "maas.io/tag-gpu": ""}))
+		g.Expect(node.Annotations).To(gomega.Equal(map[string]string{
+			infrav1beta1.NodeManagedLabelsAnnotation: "maas.io/tag-gpu,maas.io/zone",
+			infrav1beta1.NodeSystemIDAnnotation:      "abc123",
+		}))
+
+		g.Expect(applyNodeLabels(node, map[string]string{"maas.io/zone": "zone1", "maas.io/tag-gpu": ""}, "abc123")).To(gomega.BeFalse())
+	})
+
+	t.Run("labels no longer set are removed", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{"maas.io/zone": "zone1", "maas.io/tag-gpu": "", "role": "worker"},
+				Annotations: map[string]string{
+					infrav1beta1.NodeManagedLabelsAnnotation: "maas.io/tag-gpu,maas.io/zone",
+				},
+			},
+		}
+
+		g.Expect(applyNodeLabels(node, map[string]string{"maas.io/zone": "zone1"}, "")).To(gomega.BeTrue())
+		g.Expect(node.Labels).To(gomega.Equal(map[string]string{"maas.io/zone": "zone1", "role": "worker"}))
+		g.Expect(node.Annotations).To(gomega.Equal(map[string]string{infrav1beta1.NodeManagedLabelsAnnotation: "maas.io/zone"}))
+
+		g.Expect(applyNodeLabels(node, map[string]string{}, "")).To(gomega.BeTrue())
+		g.Expect(node.Labels).To(gomega.Equal(map[string]string{"role": "worker"}))
+		g.Expect(node.Annotations).To(gomega.BeEmpty())
+	})
+
+	t.Run("unmanaged node left as is", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{"maas.io/zone": "zone1"},
+			},
+		}
+
+		g.Expect(applyNodeLabels(node, map[string]string{}, "")).To(gomega.BeFalse())
+		g.Expect(node.Labels).To(gomega.Equal(map[string]string{"maas.io/zone": "zone1"}))
+	})
+}
+
+func TestMachineDrainNode(t *testing.T) {
+	t.Run("node draining excluded", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		scope := &MachineScope{
+			Logger: klogr.New(),
+			Machine: &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{clusterv1.ExcludeNodeDrainingAnnotation: ""},
+				},
+				Status: clusterv1.MachineStatus{
+					NodeRef: &corev1.ObjectReference{Name: "node"},
+				},
+			},
+		}
+
+		drained, err := scope.DrainNode()
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		g.Expect(drained).To(gomega.BeTrue())
+	})
+}
+
+func TestBootstrapTokenUsable(t *testing.T) {
+	now := time.Now()
+	data := []byte("kubeadm join 10.0.0.1:6443 --token abcdef.0123456789abcdef --discovery-token-ca-cert-hash sha256:1234")
+	tokenSecret := func(expiration time.Time) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "bootstrap-token-abcdef",
+				Namespace: metav1.NamespaceSystem,
+			},
+			Data: map[string][]byte{
+				"expiration": []byte(expiration.Format(time.RFC3339)),
+			},
+		}
+	}
+
+	t.Run("valid token", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		clientset := k8sfake.NewSimpleClientset(tokenSecret(now.Add(time.Hour)))
+
+		g.Expect(bootstrapTokenUsable(context.TODO(), clientset, data, now)).To(gomega.BeTrue())
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		clientset := k8sfake.NewSimpleClientset(tokenSecret(now.Add(-time.Minute)))
+
+		g.Expect(bootstrapTokenUsable(context.TODO(), clientset, data, now)).To(gomega.BeFalse())
+	})
+
+	t.Run("deleted token", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		clientset := k8sfake.NewSimpleClientset()
+
+		g.Expect(bootstrapTokenUsable(context.TODO(), clientset, data, now)).To(gomega.BeFalse())
+	})
+
+	t.Run("bootstrap data without token", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		clientset := k8sfake.NewSimpleClientset()
+
+		g.Expect(bootstrapTokenUsable(context.TODO(), clientset, []byte("#cloud-config"), now)).To(gomega.BeTrue())
+	})
+}
+
+func TestEvictPod(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod",
+			Namespace: "default",
+		},
+	}
+	notFound := apierrors.NewNotFound(schema.GroupResource{Resource: "pods"}, pod.Name)
+
+	// evictionReactor fails the policy/v1 evictions with NotFound, like API servers which don't serve it
+	evictionReactor := func(evicted *[]string) k8stesting.ReactionFunc {
+		return func(action k8stesting.Action) (bool, runtime.Object, error) {
+			switch action.(k8stesting.CreateAction).GetObject().(type) {
+			case *policyv1.Eviction:
+				*evicted = append(*evicted, "v1")
+				return true, nil, notFound
+			case *policyv1beta1.Eviction:
+				*evicted = append(*evicted, "v1beta1")
+				return true, nil, nil
+			}
+			return false, nil, nil
+		}
+	}
+
+	t.Run("eviction api not served", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		var evicted []string
+		clientset := k8sfake.NewSimpleClientset(pod.DeepCopy())
+		clientset.PrependReactor("create", "pods", evictionReactor(&evicted))
+
+		g.Expect(evictPod(context.TODO(), clientset, pod)).To(gomega.Succeed())
+		g.Expect(evicted).To(gomega.Equal([]string{"v1", "v1beta1"}))
+	})
+
+	t.Run("pod already deleted", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		var evicted []string
+		clientset := k8sfake.NewSimpleClientset()
+		clientset.PrependReactor("create", "pods", evictionReactor(&evicted))
+
+		g.Expect(apierrors.IsNotFound(evictPod(context.TODO(), clientset, pod))).To(gomega.BeTrue())
+		g.Expect(evicted).To(gomega.Equal([]string{"v1"}))
+	})
+}
+
+func TestMachineProviderID(t *testing.T) {
+	tests := []struct {
+		name       string
+		format     infrav1beta1.ProviderIDFormat
+		providerID string
+	}{
+		{
+			name:       "default format",
+			providerID: "maas:///zone1/abc123",
+		},
+		{
+			name:       "zone format",
+			format:     infrav1beta1.ProviderIDFormatZone,
+			providerID: "maas:///zone1/abc123",
+		},
+		{
+			name:       "system id format",
+			format:     infrav1beta1.ProviderIDFormatSystemID,
+			providerID: "maas://abc123",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			scope := &MachineScope{
+				ClusterScope: &ClusterScope{
+					MaasCluster: &infrav1beta1.MaasCluster{
+						Spec: infrav1beta1.MaasClusterSpec{
+							ProviderIDFormat: tt.format,
+						},
+					},
+				},
+				MaasMachine: &infrav1beta1.MaasMachine{},
+			}
+
+			scope.SetProviderID("abc123", "zone1")
+			g.Expect(scope.GetProviderID()).To(gomega.Equal(tt.providerID))
+			g.Expect(scope.GetInstanceID()).To(gomega.Equal(pointer.String("abc123")))
+		})
+	}
+}
+
+func TestMachineGetRawBootstrapData(t *testing.T) {
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "machine",
+			Namespace: "default",
+		},
+		Spec: clusterv1.MachineSpec{
+			Bootstrap: clusterv1.Bootstrap{
+				DataSecretName: pointer.String("bootstrap"),
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		secret  *corev1.Secret
+		wantErr error
+	}{
+		{
+			name:    "secret not found",
+			wantErr: ErrBootstrapDataSecretNotFound,
+		},
+		{
+			name: "value key missing",
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "bootstrap", Namespace: "default"},
+				Data:       map[string][]byte{"format": []byte("cloud-config")},
+			},
+			wantErr: ErrBootstrapDataKeyMissing,
+		},
+		{
+			name: "value empty",
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "bootstrap", Namespace: "default"},
+				Data:       map[string][]byte{"value": {}},
+			},
+			wantErr: ErrBootstrapDataEmpty,
+		},
+		{
+			name: "value set",
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "bootstrap", Namespace: "default"},
+				Data:       map[string][]byte{"value": []byte("#cloud-config")},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			builder := fake.NewClientBuilder()
+			if tt.secret != nil {
+				builder = builder.WithObjects(tt.secret)
+			}
+			scope := &MachineScope{
+				client:  builder.Build(),
+				Machine: machine,
+			}
+
+			data, err := scope.GetRawBootstrapData()
+			if tt.wantErr != nil {
+				g.Expect(errors.Is(err, tt.wantErr)).To(gomega.BeTrue())
+				return
+			}
+			g.Expect(err).ToNot(gomega.HaveOccurred())
+			g.Expect(data).To(gomega.Equal([]byte("#cloud-config")))
+		})
+	}
+}
+
+func TestMachineApplyTemplateDefaults(t *testing.T) {
+	defaults := &infrav1beta1.MaasMachineTemplateDefaults{
+		FailureDomain: pointer.String("zone-default"),
+		ResourcePool:  pointer.String("pool-default"),
+		Tags:          []string{"gpu", "rack1"},
+	}
+
+	tests := []struct {
+		name        string
+		spec        infrav1beta1.MaasMachineSpec
+		machineZone *string
+		want        infrav1beta1.MaasMachineSpec
+	}{
+		{
+			name: "defaults used when machine has no placement",
+			want: infrav1beta1.MaasMachineSpec{
+				FailureDomain: pointer.String("zone-default"),
+				ResourcePool:  pointer.String("pool-default"),
+				Tags:          []string{"gpu", "rack1"},
+			},
+		},
+		{
+			name: "machine placement takes precedence and tags are appended",
+			spec: infrav1beta1.MaasMachineSpec{
+				FailureDomain: pointer.String("zone1"),
+				ResourcePool:  pointer.String("pool1"),
+				Tags:          []string{"rack1", "ssd"},
+			},
+			want: infrav1beta1.MaasMachineSpec{
+				FailureDomain: pointer.String("zone1"),
+				ResourcePool:  pointer.String("pool1"),
+				Tags:          []string{"rack1", "ssd", "gpu"},
+			},
+		},
+		{
+			name:        "machine failure domain takes precedence",
+			machineZone: pointer.String("zone2"),
+			want: infrav1beta1.MaasMachineSpec{
+				ResourcePool: pointer.String("pool-default"),
+				Tags:         []string{"gpu", "rack1"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			scope := &MachineScope{
+				Machine:     &clusterv1.Machine{Spec: clusterv1.MachineSpec{FailureDomain: tt.machineZone}},
+				MaasMachine: &infrav1beta1.MaasMachine{Spec: tt.spec},
+			}
+
+			scope.ApplyTemplateDefaults(defaults)
+			g.Expect(scope.MaasMachine.Spec).To(gomega.Equal(tt.want))
+		})
+	}
+}
+
+func TestMachineGetMachineTemplateDefaults(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	scheme := runtime.NewScheme()
+	_ = infrav1beta1.AddToScheme(scheme)
+
+	template := &infrav1beta1.MaasMachineTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "template", Namespace: "default"},
+		Spec: infrav1beta1.MaasMachineTemplateSpec{
+			Defaults: &infrav1beta1.MaasMachineTemplateDefaults{Tags: []string{"gpu"}},
+		},
+	}
+	scope := &MachineScope{
+		client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(template).Build(),
+		MaasMachine: &infrav1beta1.MaasMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "machine",
+				Namespace: "default",
+				Annotations: map[string]string{
+					clusterv1.TemplateClonedFromNameAnnotation:      "template",
+					clusterv1.TemplateClonedFromGroupKindAnnotation: "MaasMachineTemplate.infrastructure.cluster.x-k8s.io",
+				},
+			},
+		},
+	}
+
+	defaults, err := scope.GetMachineTemplateDefaults()
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(defaults).To(gomega.Equal(template.Spec.Defaults))
+
+	scope.MaasMachine.Annotations[clusterv1.TemplateClonedFromNameAnnotation] = "deleted"
+	defaults, err = scope.GetMachineTemplateDefaults()
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(defaults).To(gomega.BeNil())
+}
@@ -0,0 +1,82 @@
+package zone
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	infrav1beta1 "github.com/spectrocloud/cluster-api-provider-maas/api/v1beta1"
+	"github.com/spectrocloud/cluster-api-provider-maas/pkg/maas/scope"
+	"github.com/spectrocloud/maas-client-go/maasclient"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// Service manages the MaaS zones (failure domains) of a cluster
+type Service struct {
+	scope      *scope.ClusterScope
+	maasClient maasclient.ClientSetInterface
+}
+
+// NewService returns a new helper for looking up MaaS zones
+func NewService(clusterScope *scope.ClusterScope) *Service {
+	return &Service{
+		scope:      clusterScope,
+		maasClient: scope.NewMaasClient(clusterScope),
+	}
+}
+
+// GetZoneNames returns the names of all zones known to MaaS
+func (s *Service) GetZoneNames() (sets.String, error) {
+	zones, err := s.maasClient.Zones().List(context.Background())
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to list zones")
+	}
+
+	names := sets.NewString()
+	for _, z := range zones {
+		names.Insert(z.Name())
+	}
+
+	return names, nil
+}
+
+// GetMissingFailureDomains returns the configured failure domains that don't match any MaaS zone
+func (s *Service) GetMissingFailureDomains() ([]string, error) {
+	failureDomains := s.scope.MaasCluster.Spec.FailureDomains
+	if len(failureDomains) == 0 {
+		return nil, nil
+	}
+
+	zones, err := s.GetZoneNames()
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	for _, fd := range failureDomains {
+		if !zones.Has(fd) {
+			missing = append(missing, fd)
+		}
+	}
+
+	return missing, nil
+}
+
+// GetReadyMachineCounts returns the raw number of machines in the Ready state per zone.
+// The counts don't apply the resource pool, tags, CPU and memory constraints of any machine, they're an
+// upper bound of what the allocator accepts.
+func (s *Service) GetReadyMachineCounts() (map[string]int, error) {
+	machines, err := s.maasClient.Machines().List(context.Background(), maasclient.ParamsBuilder())
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to list machines")
+	}
+
+	counts := make(map[string]int)
+	for _, m := range machines {
+		if infrav1beta1.MachineState(m.State()) != infrav1beta1.MachineStateReady {
+			continue
+		}
+		counts[m.Zone().Name()]++
+	}
+
+	return counts, nil
+}
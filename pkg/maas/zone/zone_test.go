@@ -0,0 +1,136 @@
+package zone
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2/klogr"
+	"sigs.k8s.io/cluster-api/api/v1beta1"
+
+	infrav1beta1 "github.com/spectrocloud/cluster-api-provider-maas/api/v1beta1"
+	mockclientset "github.com/spectrocloud/cluster-api-provider-maas/pkg/maas/client/mock"
+	"github.com/spectrocloud/cluster-api-provider-maas/pkg/maas/scope"
+	"github.com/spectrocloud/maas-client-go/maasclient"
+)
+
+func TestZone(t *testing.T) {
+	log := klogr.New()
+	cluster := &v1beta1.Cluster{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "a",
+		},
+	}
+
+	t.Run("missing failure domains", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		mockClientSetInterface := mockclientset.NewMockClientSetInterface(ctrl)
+		mockZones := mockclientset.NewMockZones(ctrl)
+		mockZone1 := mockclientset.NewMockZone(ctrl)
+		mockZone2 := mockclientset.NewMockZone(ctrl)
+
+		s := &Service{
+			scope: &scope.ClusterScope{
+				Logger:  log,
+				Cluster: cluster,
+				MaasCluster: &infrav1beta1.MaasCluster{
+					Spec: infrav1beta1.MaasClusterSpec{
+						FailureDomains: []string{"zone1", "zone3"},
+					},
+				},
+			},
+			maasClient: mockClientSetInterface,
+		}
+
+		mockClientSetInterface.EXPECT().Zones().Return(mockZones)
+		mockZones.EXPECT().List(context.Background()).Return([]maasclient.Zone{mockZone1, mockZone2}, nil)
+		mockZone1.EXPECT().Name().Return("zone1")
+		mockZone2.EXPECT().Name().Return("zone2")
+
+		missing, err := s.GetMissingFailureDomains()
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(missing).To(ConsistOf("zone3"))
+	})
+
+	t.Run("no failure domains skips lookup", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		mockClientSetInterface := mockclientset.NewMockClientSetInterface(ctrl)
+
+		s := &Service{
+			scope: &scope.ClusterScope{
+				Logger:      log,
+				Cluster:     cluster,
+				MaasCluster: &infrav1beta1.MaasCluster{},
+			},
+			maasClient: mockClientSetInterface,
+		}
+
+		missing, err := s.GetMissingFailureDomains()
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(missing).To(BeEmpty())
+	})
+
+	t.Run("zone lookup error", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		mockClientSetInterface := mockclientset.NewMockClientSetInterface(ctrl)
+		mockZones := mockclientset.NewMockZones(ctrl)
+
+		s := &Service{
+			scope: &scope.ClusterScope{
+				Logger:  log,
+				Cluster: cluster,
+				MaasCluster: &infrav1beta1.MaasCluster{
+					Spec: infrav1beta1.MaasClusterSpec{
+						FailureDomains: []string{"zone1"},
+					},
+				},
+			},
+			maasClient: mockClientSetInterface,
+		}
+
+		mockClientSetInterface.EXPECT().Zones().Return(mockZones)
+		mockZones.EXPECT().List(context.Background()).Return(nil, errors.New("maas unavailable"))
+
+		_, err := s.GetMissingFailureDomains()
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("ready machine counts per zone", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		ctrl := gomock.NewController(t)
+		mockClientSetInterface := mockclientset.NewMockClientSetInterface(ctrl)
+		mockMachines := mockclientset.NewMockMachines(ctrl)
+		mockMachine1 := mockclientset.NewMockMachine(ctrl)
+		mockMachine2 := mockclientset.NewMockMachine(ctrl)
+		mockMachine3 := mockclientset.NewMockMachine(ctrl)
+		mockZone1 := mockclientset.NewMockZone(ctrl)
+
+		s := &Service{
+			scope: &scope.ClusterScope{
+				Logger:      log,
+				Cluster:     cluster,
+				MaasCluster: &infrav1beta1.MaasCluster{},
+			},
+			maasClient: mockClientSetInterface,
+		}
+
+		mockClientSetInterface.EXPECT().Machines().Return(mockMachines)
+		mockMachines.EXPECT().List(context.Background(), gomock.Any()).Return([]maasclient.Machine{mockMachine1, mockMachine2, mockMachine3}, nil)
+		mockMachine1.EXPECT().State().Return("Ready")
+		mockMachine1.EXPECT().Zone().Return(mockZone1)
+		mockMachine2.EXPECT().State().Return("Ready")
+		mockMachine2.EXPECT().Zone().Return(mockZone1)
+		mockMachine3.EXPECT().State().Return("Deployed")
+		mockZone1.EXPECT().Name().Return("zone1").Times(2)
+
+		counts, err := s.GetReadyMachineCounts()
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(counts).To(Equal(map[string]int{"zone1": 2}))
+	})
+}